@@ -0,0 +1,179 @@
+package wvapp
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// Modifier 快捷键修饰键，可按位组合
+type Modifier uint8
+
+const (
+	ModCmdOrCtrl Modifier = 1 << iota // macOS 上为 Cmd，其他平台为 Ctrl
+	ModCtrl
+	ModCmd // macOS 的 Cmd，Windows/Linux 的 Super 键
+	ModAlt // macOS 上为 Option
+	ModShift
+)
+
+// Accelerator 解析后的快捷键，例如 "CmdOrCtrl+Shift+S"
+type Accelerator struct {
+	Modifiers Modifier
+	Key       string // 规范化的按键名，例如 "S"、"F5"、"Enter"、"+"
+}
+
+var acceleratorModifiers = map[string]Modifier{
+	"cmdorctrl":        ModCmdOrCtrl,
+	"commandorcontrol": ModCmdOrCtrl,
+	"ctrl":             ModCtrl,
+	"control":          ModCtrl,
+	"cmd":              ModCmd,
+	"command":          ModCmd,
+	"super":            ModCmd,
+	"meta":             ModCmd,
+	"alt":              ModAlt,
+	"option":           ModAlt,
+	"shift":            ModShift,
+}
+
+var acceleratorKeys = map[string]string{
+	"enter":     "Enter",
+	"return":    "Enter",
+	"tab":       "Tab",
+	"space":     "Space",
+	"backspace": "Backspace",
+	"delete":    "Delete",
+	"del":       "Delete",
+	"insert":    "Insert",
+	"escape":    "Escape",
+	"esc":       "Escape",
+	"up":        "Up",
+	"down":      "Down",
+	"left":      "Left",
+	"right":     "Right",
+	"home":      "Home",
+	"end":       "End",
+	"pageup":    "PageUp",
+	"pagedown":  "PageDown",
+	"plus":      "+",
+}
+
+// acceleratorPunctuation 可以直接作为按键使用的符号
+const acceleratorPunctuation = "+-=[]\\;',./`"
+
+// ParseAccelerator 解析 "CmdOrCtrl+S"、"Alt+F4"、"Ctrl+Plus" 等写法，大小写不敏感
+func ParseAccelerator(s string) (Accelerator, error) {
+	var acc Accelerator
+	if strings.TrimSpace(s) == "" {
+		return acc, fmt.Errorf("webview: empty accelerator")
+	}
+	parts := strings.Split(s, "+")
+	// "Ctrl++" 会拆出两个空段，最后一段视为 "+" 键
+	if len(parts) >= 2 && parts[len(parts)-1] == "" && parts[len(parts)-2] == "" {
+		parts = append(parts[:len(parts)-2], "+")
+	}
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			return Accelerator{}, fmt.Errorf("webview: invalid accelerator %q", s)
+		}
+		if mod, ok := acceleratorModifiers[strings.ToLower(part)]; ok && i < len(parts)-1 {
+			if acc.Modifiers&mod != 0 {
+				return Accelerator{}, fmt.Errorf("webview: duplicate modifier %q in accelerator %q", part, s)
+			}
+			acc.Modifiers |= mod
+			continue
+		}
+		if i != len(parts)-1 {
+			return Accelerator{}, fmt.Errorf("webview: accelerator %q has more than one key", s)
+		}
+		if _, ok := acceleratorModifiers[strings.ToLower(part)]; ok {
+			return Accelerator{}, fmt.Errorf("webview: accelerator %q has no key", s)
+		}
+		key, ok := normalizeAcceleratorKey(part)
+		if !ok {
+			return Accelerator{}, fmt.Errorf("webview: unknown key %q in accelerator %q", part, s)
+		}
+		acc.Key = key
+	}
+	return acc, nil
+}
+
+func normalizeAcceleratorKey(key string) (string, bool) {
+	if name, ok := acceleratorKeys[strings.ToLower(key)]; ok {
+		return name, true
+	}
+	if len(key) == 1 {
+		c := key[0]
+		switch {
+		case c >= 'a' && c <= 'z':
+			return strings.ToUpper(key), true
+		case c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+			return key, true
+		case strings.IndexByte(acceleratorPunctuation, c) >= 0:
+			return key, true
+		}
+		return "", false
+	}
+	// F1 - F24
+	if key[0] == 'f' || key[0] == 'F' {
+		var n int
+		if _, err := fmt.Sscanf(key[1:], "%d", &n); err == nil && n >= 1 && n <= 24 && fmt.Sprint(n) == key[1:] {
+			return fmt.Sprintf("F%d", n), true
+		}
+	}
+	return "", false
+}
+
+// String 返回规范写法，ParseAccelerator(a.String()) 得到相同结果
+func (a Accelerator) String() string {
+	return a.format(map[Modifier]string{
+		ModCmdOrCtrl: "CmdOrCtrl",
+		ModCtrl:      "Ctrl",
+		ModCmd:       "Cmd",
+		ModAlt:       "Alt",
+		ModShift:     "Shift",
+	})
+}
+
+// ForOS 将 CmdOrCtrl 展开为目标平台的实际修饰键，结果传给原生库
+func (a Accelerator) ForOS(goos string) string {
+	resolved := a
+	if resolved.Modifiers&ModCmdOrCtrl != 0 {
+		resolved.Modifiers &^= ModCmdOrCtrl
+		if goos == "darwin" {
+			resolved.Modifiers |= ModCmd
+		} else {
+			resolved.Modifiers |= ModCtrl
+		}
+	}
+	names := map[Modifier]string{ModCtrl: "Ctrl", ModCmd: "Super", ModAlt: "Alt", ModShift: "Shift"}
+	if goos == "darwin" {
+		names[ModCmd] = "Cmd"
+		names[ModAlt] = "Option"
+	}
+	return resolved.format(names)
+}
+
+func (a Accelerator) format(names map[Modifier]string) string {
+	var parts []string
+	for _, mod := range []Modifier{ModCmdOrCtrl, ModCtrl, ModCmd, ModAlt, ModShift} {
+		if a.Modifiers&mod != 0 {
+			parts = append(parts, names[mod])
+		}
+	}
+	return strings.Join(append(parts, a.Key), "+")
+}
+
+// nativeAccelerator 解析并转换为当前平台的写法，空字符串表示无快捷键
+func nativeAccelerator(s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+	acc, err := ParseAccelerator(s)
+	if err != nil {
+		return "", err
+	}
+	return acc.ForOS(runtime.GOOS), nil
+}
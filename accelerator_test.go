@@ -0,0 +1,73 @@
+package wvapp
+
+import "testing"
+
+func TestParseAccelerator(t *testing.T) {
+	tests := []struct {
+		in        string
+		want      Accelerator
+		canonical string
+	}{
+		{"CmdOrCtrl+S", Accelerator{Modifiers: ModCmdOrCtrl, Key: "S"}, "CmdOrCtrl+S"},
+		{"commandorcontrol+shift+s", Accelerator{Modifiers: ModCmdOrCtrl | ModShift, Key: "S"}, "CmdOrCtrl+Shift+S"},
+		{"Shift+Alt+Ctrl+F5", Accelerator{Modifiers: ModCtrl | ModAlt | ModShift, Key: "F5"}, "Ctrl+Alt+Shift+F5"},
+		{"Option+Esc", Accelerator{Modifiers: ModAlt, Key: "Escape"}, "Alt+Escape"},
+		{"Super+Space", Accelerator{Modifiers: ModCmd, Key: "Space"}, "Cmd+Space"},
+		{"Ctrl+Plus", Accelerator{Modifiers: ModCtrl, Key: "+"}, "Ctrl++"},
+		{"Ctrl++", Accelerator{Modifiers: ModCtrl, Key: "+"}, "Ctrl++"},
+		{"CmdOrCtrl+-", Accelerator{Modifiers: ModCmdOrCtrl, Key: "-"}, "CmdOrCtrl+-"},
+		{"F24", Accelerator{Key: "F24"}, "F24"},
+		{" Ctrl + 1 ", Accelerator{Modifiers: ModCtrl, Key: "1"}, "Ctrl+1"},
+	}
+	for _, tt := range tests {
+		got, err := ParseAccelerator(tt.in)
+		if err != nil {
+			t.Fatalf("ParseAccelerator(%q): %v", tt.in, err)
+		}
+		if got != tt.want {
+			t.Fatalf("ParseAccelerator(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+		if got.String() != tt.canonical {
+			t.Fatalf("%q.String() = %q, want %q", tt.in, got.String(), tt.canonical)
+		}
+		if again, err := ParseAccelerator(got.String()); err != nil || again != got {
+			t.Fatalf("round trip of %q = %+v, %v", tt.in, again, err)
+		}
+	}
+}
+
+func TestParseAcceleratorErrors(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"Ctrl",
+		"Ctrl+Shift",
+		"Ctrl+Ctrl+S",
+		"Ctrl+S+T",
+		"Ctrl+F25",
+		"Ctrl+F05",
+		"Hyper+S",
+		"Ctrl++S",
+		"Ctrl+é",
+	} {
+		if acc, err := ParseAccelerator(in); err == nil {
+			t.Fatalf("ParseAccelerator(%q) = %+v, want error", in, acc)
+		}
+	}
+}
+
+func TestAcceleratorForOS(t *testing.T) {
+	acc, err := ParseAccelerator("CmdOrCtrl+Alt+S")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := acc.ForOS("darwin"); got != "Cmd+Option+S" {
+		t.Fatalf("darwin: %q", got)
+	}
+	if got := acc.ForOS("windows"); got != "Ctrl+Alt+S" {
+		t.Fatalf("windows: %q", got)
+	}
+	acc, _ = ParseAccelerator("CmdOrCtrl+Ctrl+Q")
+	if got := acc.ForOS("linux"); got != "Ctrl+Q" {
+		t.Fatalf("CmdOrCtrl+Ctrl on linux should collapse, got %q", got)
+	}
+}
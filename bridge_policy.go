@@ -0,0 +1,254 @@
+package wvapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// 调用来源的 frame 类型
+const (
+	FrameMain     = "main"
+	FrameSubframe = "subframe"
+)
+
+// OriginPolicy 控制哪些来源的页面可以通过 _runtime_invoke 调用 Go 函数
+//
+// AllowedOrigins 支持以下写法：
+//   - 完整来源："wvapp://app"、"https://example.com"、"http://localhost:8080"
+//   - 通配子域名："https://*.example.com"（不匹配 example.com 本身）
+//   - 仅 scheme："wvapp:"，匹配该 scheme 下的任意来源
+//   - "null"：匹配不透明来源（例如 SetHtml 加载的页面）
+//
+// 调用来源与 frame 由原生库报告（webview_bridge_caller）。原生库不支持时页面自报的 frame 无法校验，
+// iframe 可以冒充主 frame，因此设置了策略的窗口会拒绝所有调用。
+type OriginPolicy struct {
+	AllowedOrigins []string
+	AllowSubframes bool // 是否允许 iframe 调用（默认仅允许主 frame）
+}
+
+// bridgeCaller 一次桥接调用的来源
+type bridgeCaller struct {
+	Origin string `json:"origin"`
+	Frame  string `json:"frame"`
+	URL    string `json:"url"`
+}
+
+var (
+	bridgePolicyRegistry = make(map[*Webview]*OriginPolicy)
+	bridgeMainURLs       = make(map[*Webview]string) // 导航完成事件记录的主 frame URL
+	bridgePolicyMutex    sync.RWMutex
+
+	// queryBridgeCaller 向原生库查询当前调用的来源，ok 为 false 表示原生库无法报告，测试中替换
+	queryBridgeCaller = func(w *Webview) (caller bridgeCaller, ok bool, err error) {
+		if webviewBridgeCaller == nil || webviewFreeString == nil {
+			return caller, false, nil
+		}
+		ptr := webviewBridgeCaller(w)
+		if ptr == 0 {
+			return caller, false, nil
+		}
+		data := goString(ptr)
+		webviewFreeString(ptr)
+		if err := json.Unmarshal([]byte(data), &caller); err != nil {
+			return caller, false, fmt.Errorf("invalid caller info: %w", err)
+		}
+		return caller, true, nil
+	}
+)
+
+// Allows 判断来自 origin/frame 的调用是否被允许
+func (p *OriginPolicy) Allows(origin, frame string) bool {
+	if p == nil {
+		return true
+	}
+	if frame != FrameMain && !p.AllowSubframes {
+		return false
+	}
+	origin = normalizeOrigin(origin)
+	if origin == "" {
+		return false
+	}
+	for _, allowed := range p.AllowedOrigins {
+		if matchOrigin(normalizeOrigin(allowed), origin) {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeOrigin(origin string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(origin)), "/")
+}
+
+func matchOrigin(pattern, origin string) bool {
+	if pattern == "" {
+		return false
+	}
+	if pattern == origin {
+		return true
+	}
+	// "scheme:" 匹配该 scheme 下的任意来源
+	if strings.HasSuffix(pattern, ":") {
+		return strings.HasPrefix(origin, pattern)
+	}
+	scheme, host, ok := strings.Cut(pattern, "://")
+	if !ok || !strings.HasPrefix(host, "*.") {
+		return false
+	}
+	oScheme, oHost, ok := strings.Cut(origin, "://")
+	if !ok || oScheme != scheme {
+		return false
+	}
+	return strings.HasSuffix(oHost, host[1:])
+}
+
+// urlOrigin 返回页面 URL 对应的 window.location.origin，about:blank、data: 等为 "null"
+func urlOrigin(raw string) string {
+	origin, err := permissionOrigin(raw)
+	if err != nil {
+		return "null"
+	}
+	return origin
+}
+
+// sameOrigin 比较页面自报的来源与窗口 URL 的来源；本地文件在不同引擎中为 "null" 或 "file://"
+func sameOrigin(claimed, actual string) bool {
+	claimed = normalizeOrigin(claimed)
+	if claimed == actual {
+		return true
+	}
+	opaque := func(o string) bool { return o == "null" || o == "file://" }
+	return opaque(claimed) && opaque(actual)
+}
+
+// isAppOrigin 判断是否为应用自身的来源：已注册的 URI scheme、SetHtml 或本地文件页面、本机开发服务器
+func isAppOrigin(origin string) bool {
+	origin = normalizeOrigin(origin)
+	if origin == "null" || origin == "file://" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	if name, ok := registeredScheme.Load().(string); ok && name != "" && strings.EqualFold(u.Scheme, name) {
+		return true
+	}
+	if u.Scheme == "http" || u.Scheme == "https" {
+		switch u.Hostname() {
+		case "localhost", "127.0.0.1", "::1":
+			return true
+		}
+	}
+	return false
+}
+
+// allowDefault 未设置策略时的规则：内置的 _go_runtime_ 函数（剪贴板、对话框、清除数据等）
+// 只允许应用自身来源的主 frame 调用，其他函数不限制
+func allowDefault(fn string, c bridgeCaller) bool {
+	if !strings.HasPrefix(fn, "_go_runtime_") {
+		return true
+	}
+	return c.Frame == FrameMain && isAppOrigin(c.Origin)
+}
+
+// SetBridgeOriginPolicy 设置窗口的桥接来源策略，nil 表示使用默认规则：
+// 内置的 _go_runtime_ 函数只允许应用自身来源（已注册的 URI scheme、SetHtml 或本地文件页面、
+// localhost）的主 frame 调用，通过 Bind 或 UserFunctionRegistry 注册的函数不限制
+func (w *Webview) SetBridgeOriginPolicy(policy *OriginPolicy) {
+	bridgePolicyMutex.Lock()
+	defer bridgePolicyMutex.Unlock()
+	if policy == nil {
+		delete(bridgePolicyRegistry, w)
+		return
+	}
+	p := *policy
+	p.AllowedOrigins = append([]string(nil), policy.AllowedOrigins...)
+	bridgePolicyRegistry[w] = &p
+}
+
+// trackBridgeOrigin 记录主 frame 的 URL，原生库无法报告调用来源时用于校验页面自报的来源。
+// 只在导航完成时记录：导航开始时导航策略可能还会拒绝该 URL
+func (w *Webview) trackBridgeOrigin() {
+	w.On(EventNavigationFinished, func(wv *Webview, ev Event) {
+		bridgePolicyMutex.Lock()
+		defer bridgePolicyMutex.Unlock()
+		bridgeMainURLs[wv] = ev.(NavigationFinishedEvent).URL
+	})
+}
+
+// trustedPage 当前主 frame 页面是否可以收到本地文件路径等敏感数据：
+// 设置了策略时为策略允许的来源，否则为应用自身的来源；尚未记录页面 URL 时视为不可信
+func (w *Webview) trustedPage() bool {
+	bridgePolicyMutex.RLock()
+	mainURL, tracked := bridgeMainURLs[w]
+	policy := bridgePolicyRegistry[w]
+	bridgePolicyMutex.RUnlock()
+	if !tracked {
+		return false
+	}
+	origin := urlOrigin(mainURL)
+	if policy != nil {
+		return policy.Allows(origin, FrameMain)
+	}
+	return isAppOrigin(origin)
+}
+
+// resolveBridgeCaller 确定调用来源，verified 表示来源与 frame 由原生库报告。
+// 原生库无法报告时用记录的主 frame URL 校验页面自报的主 frame 来源，但 frame 本身无法校验。
+// 必须在绑定回调（主线程）中调用
+func (w *Webview) resolveBridgeCaller(p CallPayload) (caller bridgeCaller, verified bool, err error) {
+	if caller, ok, err := queryBridgeCaller(w); ok || err != nil {
+		return caller, ok, err
+	}
+	claimed := bridgeCaller{Origin: p.Origin, Frame: p.Frame, URL: p.URL}
+	bridgePolicyMutex.RLock()
+	mainURL, tracked := bridgeMainURLs[w]
+	bridgePolicyMutex.RUnlock()
+	if !tracked || claimed.Frame != FrameMain {
+		return claimed, false, nil
+	}
+	actual := urlOrigin(mainURL)
+	if !sameOrigin(claimed.Origin, actual) {
+		return claimed, false, fmt.Errorf("origin %q does not match the main frame %q", claimed.Origin, actual)
+	}
+	claimed.Origin = actual
+	return claimed, false, nil
+}
+
+// allowBridgeCall 校验调用来源，拒绝时记录审计日志
+func (w *Webview) allowBridgeCall(p CallPayload) bool {
+	caller, verified, err := w.resolveBridgeCaller(p)
+	if err == nil {
+		bridgePolicyMutex.RLock()
+		policy := bridgePolicyRegistry[w]
+		bridgePolicyMutex.RUnlock()
+		switch {
+		case policy == nil:
+			if allowDefault(p.Func, caller) {
+				return true
+			}
+			err = fmt.Errorf("origin not allowed")
+		case !verified:
+			// 显式策略无法在页面自报的 frame 上执行，宁可拒绝
+			err = fmt.Errorf("native library does not report bridge callers, origin policy cannot be enforced")
+		case policy.Allows(caller.Origin, caller.Frame):
+			return true
+		default:
+			err = fmt.Errorf("origin not allowed")
+		}
+	}
+	slog.Warn("[Bridge Audit] call rejected",
+		"window", w.ID(),
+		"function", p.Func,
+		"origin", caller.Origin,
+		"frame", caller.Frame,
+		"url", caller.URL,
+		"reason", err,
+	)
+	return false
+}
@@ -0,0 +1,169 @@
+package wvapp
+
+import "testing"
+
+func TestOriginPolicyAllows(t *testing.T) {
+	policy := &OriginPolicy{
+		AllowedOrigins: []string{"wvapp:", "https://example.com", "https://*.trusted.org/", "null"},
+	}
+	cases := []struct {
+		origin, frame string
+		want          bool
+	}{
+		{"wvapp://index.html", FrameMain, true},
+		{"WVAPP://app", FrameMain, true},
+		{"https://example.com", FrameMain, true},
+		{"https://example.com:8443", FrameMain, false},
+		{"http://example.com", FrameMain, false},
+		{"https://evil-example.com", FrameMain, false},
+		{"https://api.trusted.org", FrameMain, true},
+		{"https://trusted.org", FrameMain, false},
+		{"https://eviltrusted.org", FrameMain, false},
+		{"null", FrameMain, true},
+		{"", FrameMain, false},
+		{"https://example.com", FrameSubframe, false},
+		{"https://example.com", "", false},
+	}
+	for _, c := range cases {
+		if got := policy.Allows(c.origin, c.frame); got != c.want {
+			t.Errorf("Allows(%q, %q) = %v, want %v", c.origin, c.frame, got, c.want)
+		}
+	}
+
+	policy.AllowSubframes = true
+	if !policy.Allows("https://example.com", FrameSubframe) {
+		t.Error("expected subframe call to be allowed when AllowSubframes is set")
+	}
+
+	var unrestricted *OriginPolicy
+	if !unrestricted.Allows("", "") {
+		t.Error("nil policy should allow every call")
+	}
+}
+
+// stubBridgeCaller 让原生库报告固定的调用来源，返回恢复函数
+func stubBridgeCaller(caller *bridgeCaller) func() {
+	original := queryBridgeCaller
+	queryBridgeCaller = func(*Webview) (bridgeCaller, bool, error) {
+		if caller == nil {
+			return bridgeCaller{}, false, nil
+		}
+		return *caller, true, nil
+	}
+	return func() { queryBridgeCaller = original }
+}
+
+func TestAllowBridgeCallUsesWindowPolicy(t *testing.T) {
+	wv := fakeWebview()
+	defer releaseWindow(wv)
+	native := &bridgeCaller{Origin: "https://ads.example.net", Frame: FrameMain}
+	defer stubBridgeCaller(native)()
+
+	call := CallPayload{Func: "greet", Origin: "https://ads.example.net", Frame: FrameMain}
+	if !wv.allowBridgeCall(call) {
+		t.Fatal("user functions should be allowed before a policy is set")
+	}
+
+	origins := []string{"wvapp:"}
+	wv.SetBridgeOriginPolicy(&OriginPolicy{AllowedOrigins: origins})
+	origins[0] = "https:" // 策略应保存副本
+	if wv.allowBridgeCall(call) {
+		t.Fatal("call from a foreign origin should be rejected")
+	}
+	native.Origin = "wvapp://app"
+	if !wv.allowBridgeCall(call) {
+		t.Fatal("call from the app scheme should be allowed")
+	}
+	// 原生库报告的 frame 优先于页面自报的 frame
+	native.Frame = FrameSubframe
+	if wv.allowBridgeCall(call) {
+		t.Fatal("subframe calls should be rejected unless AllowSubframes is set")
+	}
+}
+
+func TestAllowBridgeCallPolicyNeedsNativeCaller(t *testing.T) {
+	wv := fakeWebview()
+	defer releaseWindow(wv)
+	defer stubBridgeCaller(nil)()
+	wv.SetBridgeOriginPolicy(&OriginPolicy{AllowedOrigins: []string{"wvapp:"}})
+	wv.trackBridgeOrigin()
+	wv.dispatchEvent(NavigationFinishedEvent{URL: "wvapp://app/index.html"})
+
+	// iframe 可以自称主 frame 并冒用顶层页面的来源，无法校验时拒绝
+	if wv.allowBridgeCall(CallPayload{Func: "greet", Origin: "wvapp://app", Frame: FrameMain}) {
+		t.Fatal("an explicit policy must fail closed without native caller info")
+	}
+}
+
+func TestAllowBridgeCallDefaultPolicy(t *testing.T) {
+	registeredScheme.Store("wvapp")
+	defer registeredScheme.Store("")
+	wv := fakeWebview()
+	defer releaseWindow(wv)
+
+	cases := []struct {
+		origin, frame string
+		want          bool
+	}{
+		{"wvapp://app", FrameMain, true},
+		{"null", FrameMain, true},
+		{"http://localhost:5173", FrameMain, true},
+		{"http://127.0.0.1:8080", FrameMain, true},
+		{"https://ads.example.net", FrameMain, false},
+		{"wvapp://app", FrameSubframe, false},
+	}
+	for _, c := range cases {
+		call := CallPayload{Func: "_go_runtime_clipboardRead", Origin: c.origin, Frame: c.frame}
+		if got := wv.allowBridgeCall(call); got != c.want {
+			t.Errorf("runtime call from %q (%s) allowed = %v, want %v", c.origin, c.frame, got, c.want)
+		}
+	}
+	if !wv.allowBridgeCall(CallPayload{Func: "_js_console_log", Origin: "https://ads.example.net", Frame: FrameSubframe}) {
+		t.Error("non-runtime functions should not be restricted by the default policy")
+	}
+}
+
+func TestAllowBridgeCallRejectsSpoofedOrigin(t *testing.T) {
+	registeredScheme.Store("wvapp")
+	defer registeredScheme.Store("")
+	wv := fakeWebview()
+	defer releaseWindow(wv)
+	defer stubBridgeCaller(nil)()
+	wv.trackBridgeOrigin()
+
+	wv.dispatchEvent(NavigationFinishedEvent{URL: "https://evil.example.net/page"})
+	spoofed := CallPayload{Func: "_go_runtime_closeWindow", Origin: "wvapp://app", Frame: FrameMain}
+	if wv.allowBridgeCall(spoofed) {
+		t.Fatal("payload claiming an origin other than the main frame's should be rejected")
+	}
+	if wv.allowBridgeCall(CallPayload{Func: "_go_runtime_closeWindow", Origin: "https://evil.example.net", Frame: FrameMain}) {
+		t.Fatal("the real origin is not an app origin")
+	}
+
+	// 导航开始时 URL 可能仍被导航策略拒绝，不能据此放行
+	wv.dispatchEvent(NavigationStartedEvent{URL: "wvapp://app/index.html"})
+	if wv.allowBridgeCall(spoofed) {
+		t.Fatal("a navigation that has only started must not change the tracked origin")
+	}
+	wv.dispatchEvent(NavigationFinishedEvent{URL: "wvapp://app/index.html"})
+	if !wv.allowBridgeCall(spoofed) {
+		t.Fatal("call from the tracked app origin should be allowed")
+	}
+}
+
+func TestURLOrigin(t *testing.T) {
+	for in, want := range map[string]string{
+		"wvapp://app/index.html":    "wvapp://app",
+		"https://Example.com:443/x": "https://example.com",
+		"file:///tmp/index.html":    "file://",
+		"about:blank":               "null",
+		"data:text/html,<p>hi</p>":  "null",
+	} {
+		if got := urlOrigin(in); got != want {
+			t.Errorf("urlOrigin(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if !sameOrigin("null", "file://") || sameOrigin("https://a.com", "https://b.com") {
+		t.Error("sameOrigin mismatch")
+	}
+}
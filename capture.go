@@ -0,0 +1,112 @@
+package wvapp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+	"runtime"
+)
+
+// 截图区域
+var (
+	CaptureViewport = Rect{}                      // 当前可见区域
+	CaptureFullPage = Rect{Width: -1, Height: -1} // 整个页面，包括需要滚动才能看到的部分
+)
+
+// captureRequest 传给原生库的截图参数，坐标为页面（文档）坐标，单位为 CSS 像素
+type captureRequest struct {
+	X        int  `json:"x"`
+	Y        int  `json:"y"`
+	Width    int  `json:"width"`
+	Height   int  `json:"height"`
+	FullPage bool `json:"fullPage"`
+}
+
+func newCaptureRequest(rect Rect) (captureRequest, error) {
+	switch rect {
+	case CaptureViewport:
+		return captureRequest{}, nil
+	case CaptureFullPage:
+		return captureRequest{FullPage: true}, nil
+	}
+	if rect.Width <= 0 || rect.Height <= 0 || rect.X < 0 || rect.Y < 0 {
+		return captureRequest{}, fmt.Errorf("webview: invalid capture area %+v", rect)
+	}
+	return captureRequest{X: rect.X, Y: rect.Y, Width: rect.Width, Height: rect.Height}, nil
+}
+
+// Capture 截取页面渲染结果。rect 为 CaptureViewport、CaptureFullPage，
+// 或以页面左上角为原点、以 CSS 像素为单位的区域（超出可见区域的部分同样会被渲染）。
+// 返回的图像为设备像素，高 DPI 屏幕上尺寸是 rect 的整数倍；Headless 窗口同样可用
+func (w *Webview) Capture(ctx context.Context, rect Rect) (image.Image, error) {
+	data, err := w.CapturePNG(ctx, rect)
+	if err != nil {
+		return nil, err
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("webview: invalid capture image: %w", err)
+	}
+	return img, nil
+}
+
+// CapturePNG 与 Capture 相同，但返回原生库生成的 PNG 数据而不解码
+func (w *Webview) CapturePNG(ctx context.Context, rect Rect) ([]byte, error) {
+	req, err := newCaptureRequest(rect)
+	if err != nil {
+		return nil, err
+	}
+	if webviewCapture == nil {
+		return nil, ErrNotSupported
+	}
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	pngData, err := requestBytes(ctx, func(callback, id uintptr) {
+		cstr, ptr := cString(string(data))
+		webviewCapture(w, ptr, callback, id)
+		runtime.KeepAlive(cstr)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(pngData, pngSignature) {
+		return nil, fmt.Errorf("webview: native library returned invalid capture data")
+	}
+	return pngData, nil
+}
+
+// captureRectFromJS 将 JS 传入的 'viewport'、'fullPage' 或 {x, y, width, height} 转换为 Rect
+func captureRectFromJS(args []any) (Rect, error) {
+	if len(args) == 0 || args[0] == nil {
+		return CaptureViewport, nil
+	}
+	switch v := args[0].(type) {
+	case string:
+		switch v {
+		case "viewport":
+			return CaptureViewport, nil
+		case "fullPage":
+			return CaptureFullPage, nil
+		}
+		return Rect{}, fmt.Errorf("unknown capture area %q", v)
+	case map[string]any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return Rect{}, err
+		}
+		var rect Rect
+		if err := json.Unmarshal(data, &rect); err != nil {
+			return Rect{}, fmt.Errorf("invalid capture area: %w", err)
+		}
+		if rect == CaptureViewport || rect == CaptureFullPage {
+			return Rect{}, fmt.Errorf("invalid capture area %+v", rect)
+		}
+		return rect, nil
+	}
+	return Rect{}, fmt.Errorf("invalid capture area")
+}
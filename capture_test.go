@@ -0,0 +1,50 @@
+package wvapp
+
+import (
+	"testing"
+)
+
+func TestNewCaptureRequest(t *testing.T) {
+	if req, err := newCaptureRequest(CaptureViewport); err != nil || req != (captureRequest{}) {
+		t.Fatalf("viewport = %+v, %v", req, err)
+	}
+	if req, err := newCaptureRequest(CaptureFullPage); err != nil || !req.FullPage {
+		t.Fatalf("full page = %+v, %v", req, err)
+	}
+	req, err := newCaptureRequest(Rect{X: 10, Y: 2000, Width: 300, Height: 200})
+	if err != nil || req != (captureRequest{X: 10, Y: 2000, Width: 300, Height: 200}) {
+		t.Fatalf("area = %+v, %v", req, err)
+	}
+	for _, rect := range []Rect{
+		{Width: 100},
+		{X: -1, Width: 10, Height: 10},
+		{Width: -5, Height: 10},
+	} {
+		if _, err := newCaptureRequest(rect); err == nil {
+			t.Errorf("newCaptureRequest(%+v): expected an error", rect)
+		}
+	}
+}
+
+func TestCaptureRectFromJS(t *testing.T) {
+	for name, tc := range map[string]struct {
+		args []any
+		want Rect
+	}{
+		"default":   {nil, CaptureViewport},
+		"viewport":  {[]any{"viewport"}, CaptureViewport},
+		"full page": {[]any{"fullPage"}, CaptureFullPage},
+		"area": {[]any{map[string]any{"x": float64(5), "y": float64(6), "width": float64(7), "height": float64(8)}},
+			Rect{X: 5, Y: 6, Width: 7, Height: 8}},
+	} {
+		got, err := captureRectFromJS(tc.args)
+		if err != nil || got != tc.want {
+			t.Errorf("%s: captureRectFromJS = %+v, %v; want %+v", name, got, err, tc.want)
+		}
+	}
+	for _, args := range [][]any{{"page"}, {float64(1)}, {map[string]any{}}, {map[string]any{"width": "wide"}}} {
+		if _, err := captureRectFromJS(args); err == nil {
+			t.Errorf("captureRectFromJS(%v): expected an error", args)
+		}
+	}
+}
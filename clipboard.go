@@ -0,0 +1,161 @@
+package wvapp
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+// ClipboardFormat 剪贴板数据格式
+type ClipboardFormat int32
+
+const (
+	ClipboardText  ClipboardFormat = iota // UTF-8 文本
+	ClipboardHTML                         // HTML 片段
+	ClipboardImage                        // PNG 图片
+)
+
+// ClipboardBackend 剪贴板后端，测试中可用 SetClipboardBackend 替换
+//
+// Read 在剪贴板中没有该格式的数据时返回 nil 与 nil 错误。
+type ClipboardBackend interface {
+	Read(format ClipboardFormat) ([]byte, error)
+	Write(format ClipboardFormat, data []byte) error
+}
+
+var (
+	clipboardBackend      ClipboardBackend = nativeClipboardBackend{}
+	clipboardBackendMutex sync.RWMutex
+)
+
+// SetClipboardBackend 替换剪贴板后端，传入 nil 恢复原生实现
+func SetClipboardBackend(backend ClipboardBackend) {
+	if backend == nil {
+		backend = nativeClipboardBackend{}
+	}
+	clipboardBackendMutex.Lock()
+	clipboardBackend = backend
+	clipboardBackendMutex.Unlock()
+}
+
+func currentClipboardBackend() ClipboardBackend {
+	clipboardBackendMutex.RLock()
+	defer clipboardBackendMutex.RUnlock()
+	return clipboardBackend
+}
+
+type clipboardAPI struct{}
+
+// Clipboard 系统剪贴板，可在任意 goroutine 中调用，实际操作在主线程执行
+var Clipboard clipboardAPI
+
+// ReadText 读取文本，剪贴板中没有文本时返回空字符串
+func (clipboardAPI) ReadText() (string, error) {
+	data, err := currentClipboardBackend().Read(ClipboardText)
+	return string(data), err
+}
+
+// WriteText 写入文本，替换剪贴板中的所有内容
+func (clipboardAPI) WriteText(text string) error {
+	return currentClipboardBackend().Write(ClipboardText, []byte(text))
+}
+
+// ReadHTML 读取 HTML 片段
+func (clipboardAPI) ReadHTML() (string, error) {
+	data, err := currentClipboardBackend().Read(ClipboardHTML)
+	return string(data), err
+}
+
+// WriteHTML 写入 HTML 片段
+func (clipboardAPI) WriteHTML(html string) error {
+	return currentClipboardBackend().Write(ClipboardHTML, []byte(html))
+}
+
+// ReadImage 读取图片，返回 PNG 数据，没有图片时返回 nil
+func (clipboardAPI) ReadImage() ([]byte, error) {
+	return currentClipboardBackend().Read(ClipboardImage)
+}
+
+// WriteImage 写入 PNG 图片
+func (clipboardAPI) WriteImage(png []byte) error {
+	if !bytes.HasPrefix(png, pngSignature) {
+		return fmt.Errorf("webview: clipboard image must be PNG data")
+	}
+	return currentClipboardBackend().Write(ClipboardImage, png)
+}
+
+// nativeClipboardBackend 通过原生库在主线程中访问剪贴板
+type nativeClipboardBackend struct{}
+
+type clipboardResult struct {
+	data []byte
+	err  error
+}
+
+func (nativeClipboardBackend) Read(format ClipboardFormat) ([]byte, error) {
+	if err := loadWebviewLibrary(); err != nil {
+		return nil, err
+	}
+	if webviewClipboardRead == nil || webviewFreeString == nil {
+		return nil, ErrNotSupported
+	}
+	res := mainScheduler.RunInMainThreadWithResult(func() any {
+		var n uint64
+		ptr := webviewClipboardRead(int32(format), &n)
+		if ptr == nil {
+			return clipboardResult{}
+		}
+		data := bytes.Clone(unsafe.Slice(ptr, n))
+		webviewFreeString(uintptr(unsafe.Pointer(ptr)))
+		return clipboardResult{data: data}
+	}).(clipboardResult)
+	return res.data, res.err
+}
+
+func (nativeClipboardBackend) Write(format ClipboardFormat, data []byte) error {
+	if err := loadWebviewLibrary(); err != nil {
+		return err
+	}
+	if webviewClipboardWrite == nil {
+		return ErrNotSupported
+	}
+	data = bytes.Clone(data)
+	ok := mainScheduler.RunInMainThreadWithResult(func() any {
+		var ptr uintptr
+		if len(data) > 0 {
+			ptr = uintptr(unsafe.Pointer(&data[0]))
+		}
+		ok := webviewClipboardWrite(int32(format), ptr, uint64(len(data)))
+		runtime.KeepAlive(data)
+		return ok
+	}).(bool)
+	if !ok {
+		return fmt.Errorf("webview: failed to write clipboard")
+	}
+	return nil
+}
+
+// clipboardImageDataURL 将 PNG 数据转换为 JS 可直接使用的 data URL
+func clipboardImageDataURL(png []byte) string {
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(png)
+}
+
+// clipboardImageFromJS 解析 JS 传入的 data URL 或纯 base64 字符串
+func clipboardImageFromJS(s string) ([]byte, error) {
+	if strings.HasPrefix(s, "data:") {
+		meta, payload, ok := strings.Cut(s, ",")
+		if !ok || !strings.HasSuffix(meta, ";base64") {
+			return nil, fmt.Errorf("image must be a base64 data URL")
+		}
+		s = payload
+	}
+	data, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid image data: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,93 @@
+package wvapp
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+)
+
+// memoryClipboard 内存剪贴板，写入任意格式都会清空其他格式，与系统剪贴板行为一致
+type memoryClipboard struct {
+	mu   sync.Mutex
+	data map[ClipboardFormat][]byte
+}
+
+func (c *memoryClipboard) Read(format ClipboardFormat) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.data[format], nil
+}
+
+func (c *memoryClipboard) Write(format ClipboardFormat, data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.data = map[ClipboardFormat][]byte{format: data}
+	return nil
+}
+
+func TestClipboard(t *testing.T) {
+	SetClipboardBackend(&memoryClipboard{})
+	defer SetClipboardBackend(nil)
+
+	if err := Clipboard.WriteText("hello"); err != nil {
+		t.Fatal(err)
+	}
+	if text, err := Clipboard.ReadText(); err != nil || text != "hello" {
+		t.Fatalf("ReadText = %q, %v", text, err)
+	}
+	if err := Clipboard.WriteHTML("<b>hi</b>"); err != nil {
+		t.Fatal(err)
+	}
+	if html, _ := Clipboard.ReadHTML(); html != "<b>hi</b>" {
+		t.Fatalf("ReadHTML = %q", html)
+	}
+	if text, _ := Clipboard.ReadText(); text != "" {
+		t.Fatalf("ReadText after WriteHTML = %q", text)
+	}
+
+	png := append([]byte("\x89PNG\r\n\x1a\n"), 1, 2, 3)
+	if err := Clipboard.WriteImage(png); err != nil {
+		t.Fatal(err)
+	}
+	if img, _ := Clipboard.ReadImage(); !bytes.Equal(img, png) {
+		t.Fatalf("ReadImage = % x", img)
+	}
+	if err := Clipboard.WriteImage([]byte("not a png")); err == nil {
+		t.Fatal("non-PNG image should be rejected")
+	}
+}
+
+func TestClipboardFromJS(t *testing.T) {
+	SetClipboardBackend(&memoryClipboard{})
+	defer SetClipboardBackend(nil)
+	wv := fakeWebview()
+	call := func(name string, args ...any) (any, error) {
+		return UserFunctionRegistry[name](context.Background(), wv, args)
+	}
+
+	if result, err := call("_go_runtime_clipboardReadImage"); err != nil || result != nil {
+		t.Fatalf("empty image should resolve to null, got %v, %v", result, err)
+	}
+	if _, err := call("_go_runtime_clipboardWriteText", "from js"); err != nil {
+		t.Fatal(err)
+	}
+	if result, _ := call("_go_runtime_clipboardReadText"); result != "from js" {
+		t.Fatalf("ReadText from JS = %v", result)
+	}
+	if _, err := call("_go_runtime_clipboardWriteText", 42); err == nil {
+		t.Fatal("non-string text should be rejected")
+	}
+
+	png := append([]byte("\x89PNG\r\n\x1a\n"), 9)
+	dataURL := clipboardImageDataURL(png)
+	if _, err := call("_go_runtime_clipboardWriteImage", dataURL); err != nil {
+		t.Fatal(err)
+	}
+	if result, _ := call("_go_runtime_clipboardReadImage"); result != dataURL {
+		t.Fatalf("ReadImage from JS = %v", result)
+	}
+	if _, err := call("_go_runtime_clipboardWriteImage", "data:image/png,raw"); err == nil {
+		t.Fatal("non-base64 data URL should be rejected")
+	}
+}
@@ -0,0 +1,305 @@
+package wvapp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ebitengine/purego"
+)
+
+// SameSite Cookie 的 SameSite 属性
+type SameSite string
+
+const (
+	SameSiteDefault SameSite = "" // 由浏览器决定（通常等同于 Lax）
+	SameSiteLax     SameSite = "lax"
+	SameSiteStrict  SameSite = "strict"
+	SameSiteNone    SameSite = "none" // 需要 Secure
+)
+
+// Cookie 窗口数据存储中的 Cookie
+type Cookie struct {
+	Name     string
+	Value    string
+	Domain   string    // 以 "." 开头时对子域名同样有效
+	Path     string    // 默认为 "/"
+	Expires  time.Time // 零值表示会话 Cookie
+	Secure   bool
+	HTTPOnly bool
+	SameSite SameSite
+}
+
+// nativeCookie 与原生库交换的 Cookie JSON，expires 为 Unix 秒，0 表示会话 Cookie
+type nativeCookie struct {
+	Name     string   `json:"name"`
+	Value    string   `json:"value"`
+	Domain   string   `json:"domain"`
+	Path     string   `json:"path"`
+	Expires  int64    `json:"expires,omitempty"`
+	Secure   bool     `json:"secure,omitempty"`
+	HTTPOnly bool     `json:"httpOnly,omitempty"`
+	SameSite SameSite `json:"sameSite,omitempty"`
+}
+
+func (c Cookie) native() nativeCookie {
+	nc := nativeCookie{
+		Name:     c.Name,
+		Value:    c.Value,
+		Domain:   c.Domain,
+		Path:     c.Path,
+		Secure:   c.Secure,
+		HTTPOnly: c.HTTPOnly,
+		SameSite: c.SameSite,
+	}
+	if !c.Expires.IsZero() {
+		nc.Expires = c.Expires.Unix()
+	}
+	return nc
+}
+
+func (nc nativeCookie) cookie() Cookie {
+	c := Cookie{
+		Name:     nc.Name,
+		Value:    nc.Value,
+		Domain:   nc.Domain,
+		Path:     nc.Path,
+		Secure:   nc.Secure,
+		HTTPOnly: nc.HTTPOnly,
+		SameSite: SameSite(strings.ToLower(string(nc.SameSite))),
+	}
+	if nc.Expires > 0 {
+		c.Expires = time.Unix(nc.Expires, 0)
+	}
+	return c
+}
+
+// validateCookie 检查 Cookie 能否写入，Path 为空时补为 "/"
+func validateCookie(c *Cookie) error {
+	if c.Domain == "" {
+		return fmt.Errorf("webview: cookie %q needs a domain", c.Name)
+	}
+	if c.Path == "" {
+		c.Path = "/"
+	}
+	switch c.SameSite {
+	case SameSiteDefault, SameSiteLax, SameSiteStrict:
+	case SameSiteNone:
+		if !c.Secure {
+			return fmt.Errorf("webview: cookie %q with SameSite=None must be Secure", c.Name)
+		}
+	default:
+		return fmt.Errorf("webview: invalid SameSite value %q", c.SameSite)
+	}
+	hc := http.Cookie{Name: c.Name, Value: c.Value, Domain: c.Domain, Path: c.Path, Expires: c.Expires}
+	if err := hc.Valid(); err != nil {
+		return fmt.Errorf("webview: invalid cookie: %w", err)
+	}
+	return nil
+}
+
+// DataType 可清除的网站数据类型，可按位组合
+type DataType uint32
+
+const (
+	DataCookies DataType = 1 << iota
+	DataCache            // HTTP 缓存
+	DataLocalStorage
+	DataSessionStorage
+	DataIndexedDB
+	DataServiceWorkers // Service Worker 注册与 Cache Storage
+
+	DataAll = DataCookies | DataCache | DataLocalStorage | DataSessionStorage | DataIndexedDB | DataServiceWorkers
+)
+
+var dataTypeNames = []struct {
+	t    DataType
+	name string
+}{
+	{DataCookies, "cookies"},
+	{DataCache, "cache"},
+	{DataLocalStorage, "localStorage"},
+	{DataSessionStorage, "sessionStorage"},
+	{DataIndexedDB, "indexedDB"},
+	{DataServiceWorkers, "serviceWorkers"},
+}
+
+// names 原生库与 JS 使用的数据类型名称
+func (t DataType) names() []string {
+	names := []string{}
+	for _, entry := range dataTypeNames {
+		if t&entry.t != 0 {
+			names = append(names, entry.name)
+		}
+	}
+	return names
+}
+
+// ParseDataType 将名称（例如 "cookies"、"cache"、"all"）转换为 DataType
+func ParseDataType(names ...string) (DataType, error) {
+	var t DataType
+	for _, name := range names {
+		if strings.EqualFold(name, "all") {
+			t |= DataAll
+			continue
+		}
+		found := false
+		for _, entry := range dataTypeNames {
+			if strings.EqualFold(name, entry.name) {
+				t |= entry.t
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("webview: unknown data type %q", name)
+		}
+	}
+	return t, nil
+}
+
+// DataManager 管理窗口所用数据存储中的 Cookie、缓存与网页存储。
+// 同一数据目录（或同为默认配置）的窗口共享数据，对其中一个窗口的修改对其他窗口同样可见。
+type DataManager struct {
+	w *Webview
+}
+
+// Data 返回窗口的数据管理器
+func (w *Webview) Data() *DataManager {
+	return &DataManager{w: w}
+}
+
+// 原生数据操作
+const (
+	dataOpGetCookies int32 = iota
+	dataOpSetCookie
+	dataOpDeleteCookies
+	dataOpClear
+)
+
+// dataResult 原生库通过回调返回的结果
+type dataResult struct {
+	data string
+	err  string
+}
+
+var (
+	dataCallback     uintptr
+	dataCallbackOnce sync.Once
+	dataRequests     nativeRequests[dataResult]
+)
+
+// cDataHandler 原生数据操作完成时回调，result 为 JSON 结果，errMsg 非空表示失败；
+// 两个字符串只在回调期间有效
+func cDataHandler(id uintptr, result uintptr, errMsg uintptr) uintptr {
+	dataRequests.resolve(id, dataResult{data: goString(result), err: goString(errMsg)})
+	return 0
+}
+
+// request 执行原生数据操作。WebKit/WKWebView/WebView2 的存储接口都是异步的，
+// 主线程任务只负责发起请求，结果通过回调送回，ctx 取消时不再等待
+func (m *DataManager) request(ctx context.Context, op int32, payload any) (string, error) {
+	if err := loadWebviewLibrary(); err != nil {
+		return "", err
+	}
+	if webviewDataRequest == nil {
+		return "", ErrNotSupported
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	dataCallbackOnce.Do(func() { dataCallback = purego.NewCallback(cDataHandler) })
+
+	res, err := dataRequests.do(ctx, func(id uintptr) {
+		cstr, ptr := cString(string(data))
+		webviewDataRequest(m.w, op, ptr, dataCallback, id)
+		runtime.KeepAlive(cstr)
+	})
+	if err != nil {
+		return "", err
+	}
+	if res.err != "" {
+		return "", errors.New("webview: " + res.err)
+	}
+	return res.data, nil
+}
+
+// Cookies 返回会随请求发送到 rawURL 的 Cookie（包括 HttpOnly），rawURL 为空时返回全部 Cookie
+func (m *DataManager) Cookies(ctx context.Context, rawURL string) ([]Cookie, error) {
+	if rawURL != "" {
+		if u, err := url.Parse(rawURL); err != nil || !u.IsAbs() {
+			return nil, fmt.Errorf("webview: invalid cookie URL %q", rawURL)
+		}
+	}
+	result, err := m.request(ctx, dataOpGetCookies, map[string]string{"url": rawURL})
+	if err != nil {
+		return nil, err
+	}
+	var list []nativeCookie
+	if result != "" {
+		if err := json.Unmarshal([]byte(result), &list); err != nil {
+			return nil, fmt.Errorf("webview: invalid cookie list: %w", err)
+		}
+	}
+	cookies := make([]Cookie, len(list))
+	for i, nc := range list {
+		cookies[i] = nc.cookie()
+	}
+	return cookies, nil
+}
+
+// SetCookie 写入或替换 Cookie（按 Name、Domain、Path 匹配），例如在加载远程页面前写入登录凭据
+func (m *DataManager) SetCookie(ctx context.Context, c Cookie) error {
+	if err := validateCookie(&c); err != nil {
+		return err
+	}
+	_, err := m.request(ctx, dataOpSetCookie, c.native())
+	return err
+}
+
+// DeleteCookies 删除会随请求发送到 rawURL 且名为 name 的 Cookie，name 为空时删除该 URL 的全部 Cookie
+func (m *DataManager) DeleteCookies(ctx context.Context, rawURL, name string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || !u.IsAbs() {
+		return fmt.Errorf("webview: invalid cookie URL %q", rawURL)
+	}
+	_, err = m.request(ctx, dataOpDeleteCookies, map[string]string{"url": rawURL, "name": name})
+	return err
+}
+
+// Clear 清除指定类型的数据，例如退出登录时 Clear(ctx, DataCookies|DataLocalStorage)
+func (m *DataManager) Clear(ctx context.Context, types DataType) error {
+	if types&DataAll == 0 {
+		return fmt.Errorf("webview: no data types to clear")
+	}
+	_, err := m.request(ctx, dataOpClear, map[string][]string{"types": (types & DataAll).names()})
+	return err
+}
+
+// dataStoreOptions 校验 WindowOptions.DataDir/Ephemeral，返回数据目录的绝对路径
+func dataStoreOptions(options *WindowOptions) (string, error) {
+	if options.DataDir == "" {
+		return "", nil
+	}
+	if options.Ephemeral {
+		return "", fmt.Errorf("webview: DataDir and Ephemeral cannot be combined")
+	}
+	dir, err := filepath.Abs(options.DataDir)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("webview: cannot create data directory: %w", err)
+	}
+	return dir, nil
+}
@@ -0,0 +1,116 @@
+package wvapp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestValidateCookie(t *testing.T) {
+	ok := Cookie{Name: "session", Value: "abc", Domain: ".example.com"}
+	if err := validateCookie(&ok); err != nil {
+		t.Fatalf("validateCookie: %v", err)
+	}
+	if ok.Path != "/" {
+		t.Fatalf("Path = %q, want /", ok.Path)
+	}
+
+	for name, c := range map[string]Cookie{
+		"no domain":         {Name: "a", Value: "b"},
+		"no name":           {Value: "b", Domain: "example.com"},
+		"bad name":          {Name: "a b", Value: "b", Domain: "example.com"},
+		"bad value":         {Name: "a", Value: "b;c", Domain: "example.com"},
+		"none without tls":  {Name: "a", Domain: "example.com", SameSite: SameSiteNone},
+		"unknown same site": {Name: "a", Domain: "example.com", SameSite: "loose"},
+	} {
+		if err := validateCookie(&c); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestCookieNativeRoundTrip(t *testing.T) {
+	c := Cookie{
+		Name: "id", Value: "1", Domain: "example.com", Path: "/app",
+		Expires: time.Unix(1700000000, 0), Secure: true, HTTPOnly: true, SameSite: SameSiteStrict,
+	}
+	if got := c.native().cookie(); !reflect.DeepEqual(got, c) {
+		t.Fatalf("round trip = %+v, want %+v", got, c)
+	}
+	session := Cookie{Name: "s", Domain: "example.com", Path: "/"}
+	if nc := session.native(); nc.Expires != 0 {
+		t.Fatalf("session cookie expires = %d, want 0", nc.Expires)
+	}
+	if got := (nativeCookie{Name: "x", SameSite: "Lax"}).cookie(); got.SameSite != SameSiteLax || !got.Expires.IsZero() {
+		t.Fatalf("cookie() = %+v", got)
+	}
+}
+
+func TestParseDataType(t *testing.T) {
+	types, err := ParseDataType("cookies", "LocalStorage")
+	if err != nil || types != DataCookies|DataLocalStorage {
+		t.Fatalf("ParseDataType = %v, %v", types, err)
+	}
+	if all, _ := ParseDataType("all"); all != DataAll {
+		t.Fatalf("all = %v", all)
+	}
+	if _, err := ParseDataType("history"); err == nil {
+		t.Fatal("expected an error for an unknown type")
+	}
+	want := []string{"cookies", "cache", "localStorage", "sessionStorage", "indexedDB", "serviceWorkers"}
+	if got := DataAll.names(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("names = %v", got)
+	}
+}
+
+func TestDataStoreOptions(t *testing.T) {
+	if dir, err := dataStoreOptions(&WindowOptions{Ephemeral: true}); err != nil || dir != "" {
+		t.Fatalf("ephemeral = %q, %v", dir, err)
+	}
+	base := t.TempDir()
+	if _, err := dataStoreOptions(&WindowOptions{DataDir: base, Ephemeral: true}); err == nil {
+		t.Fatal("expected an error for DataDir with Ephemeral")
+	}
+	want := filepath.Join(base, "profiles", "work")
+	dir, err := dataStoreOptions(&WindowOptions{DataDir: want})
+	if err != nil || dir != want {
+		t.Fatalf("dataStoreOptions = %q, %v", dir, err)
+	}
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		t.Fatalf("data directory not created: %v", err)
+	}
+}
+
+func TestDataResultCallback(t *testing.T) {
+	// 原生回调的字符串只能来自原生内存，这里在字符串层面送回结果
+	id, ch := dataRequests.add()
+	dataRequests.resolve(id, dataResult{data: `[{"name":"a"}]`})
+	res, err := dataRequests.wait(context.Background(), id, ch)
+	if err != nil || res.data != `[{"name":"a"}]` || res.err != "" {
+		t.Fatalf("data result = %+v, %v", res, err)
+	}
+
+	// 空指针表示没有结果也没有错误
+	id, ch = dataRequests.add()
+	cDataHandler(id, 0, 0)
+	if res, err := dataRequests.wait(context.Background(), id, ch); err != nil || res != (dataResult{}) {
+		t.Fatalf("empty result = %+v, %v", res, err)
+	}
+}
+
+func TestDataResultCanceled(t *testing.T) {
+	id, ch := dataRequests.add()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := dataRequests.wait(ctx, id, ch); err != context.Canceled {
+		t.Fatalf("error = %v, want context.Canceled", err)
+	}
+	if dataRequests.isPending(id) {
+		t.Fatal("canceled request is still pending")
+	}
+	// 取消后到达的回调被忽略
+	cDataHandler(id, 0, 0)
+}
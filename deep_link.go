@@ -0,0 +1,348 @@
+package wvapp
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/ebitengine/purego"
+)
+
+// DeepLinkOptions 将应用注册为自定义协议（例如 myapp://）的系统处理程序
+type DeepLinkOptions struct {
+	Schemes   []string // 协议名，不含 "://"
+	Name      string   // 显示名称，默认为可执行文件名
+	Exec      string   // 可执行文件路径，默认为当前程序
+	Icon      string   // 图标名称或路径（Linux .desktop 的 Icon 字段）
+	DesktopID string   // Linux .desktop 文件名（不含扩展名），默认为可执行文件名
+}
+
+// DeepLink 传给 OnDeepLink 回调与 JS "deeplink" 事件的数据
+type DeepLink struct {
+	URL    string            `json:"url"`
+	Scheme string            `json:"scheme"`
+	Host   string            `json:"host"`
+	Path   string            `json:"path"`
+	Query  map[string]string `json:"query"` // 每个参数只保留第一个值
+}
+
+var (
+	validScheme    = regexp.MustCompile(`^[a-z][a-z0-9+.-]*$`)
+	reservedScheme = map[string]bool{
+		"http": true, "https": true, "file": true, "ftp": true, "mailto": true,
+		"about": true, "data": true, "javascript": true, "blob": true,
+	}
+
+	deepLinkMutex    sync.Mutex
+	deepLinkSchemes  map[string]bool
+	deepLinkHandler  func(link DeepLink)
+	deepLinkPending  []DeepLink // 没有窗口时收到的链接，交给下一个创建的窗口
+	deepLinkWindows  = make(map[*Webview]*deepLinkQueue)
+	deepLinkCallback uintptr
+	deepLinkOnce     sync.Once
+
+	// runCommand 执行注册所需的系统命令，测试中替换
+	runCommand = func(name string, args ...string) error {
+		return exec.Command(name, args...).Run()
+	}
+)
+
+// deepLinkQueue 窗口页面尚未就绪（runtime.js 未加载）时暂存发给该窗口的链接
+type deepLinkQueue struct {
+	ready   bool
+	pending []DeepLink
+}
+
+// deepLinkQueueLocked 返回窗口的链接队列，不存在时创建。调用方必须持有 deepLinkMutex。
+func deepLinkQueueLocked(w *Webview) *deepLinkQueue {
+	q := deepLinkWindows[w]
+	if q == nil {
+		q = &deepLinkQueue{}
+		deepLinkWindows[w] = q
+	}
+	return q
+}
+
+func validateScheme(scheme string) error {
+	if !validScheme.MatchString(scheme) {
+		return fmt.Errorf("webview: invalid URL scheme %q", scheme)
+	}
+	if reservedScheme[scheme] {
+		return fmt.Errorf("webview: URL scheme %q is reserved", scheme)
+	}
+	return nil
+}
+
+// OnDeepLink 设置深度链接回调。schemes 为应用处理的协议，handler 在独立 goroutine 中调用。
+// 链接同时以 "deeplink" 事件发给所有窗口的页面。
+func OnDeepLink(schemes []string, handler func(link DeepLink)) error {
+	set := make(map[string]bool, len(schemes))
+	for _, scheme := range schemes {
+		scheme = strings.ToLower(scheme)
+		if err := validateScheme(scheme); err != nil {
+			return err
+		}
+		set[scheme] = true
+	}
+	deepLinkMutex.Lock()
+	deepLinkSchemes = set
+	deepLinkHandler = handler
+	deepLinkMutex.Unlock()
+	installOpenURLCallback()
+	return nil
+}
+
+// HandleDeepLinkArgs 从命令行参数中找出深度链接并分发，返回是否找到。
+// 启动时传入 os.Args[1:]，配合 SingleInstance 时在 onSecondInstance 中传入转发的参数。
+func HandleDeepLinkArgs(args []string) bool {
+	links := parseDeepLinks(args)
+	for _, link := range links {
+		dispatchDeepLink(link)
+	}
+	return len(links) > 0
+}
+
+// parseDeepLinks 解析属于已注册协议的参数
+func parseDeepLinks(args []string) []DeepLink {
+	deepLinkMutex.Lock()
+	schemes := deepLinkSchemes
+	deepLinkMutex.Unlock()
+
+	var links []DeepLink
+	for _, arg := range args {
+		u, err := url.Parse(arg)
+		if err != nil || !u.IsAbs() || !schemes[strings.ToLower(u.Scheme)] {
+			continue
+		}
+		link := DeepLink{
+			URL:    arg,
+			Scheme: strings.ToLower(u.Scheme),
+			Host:   u.Host,
+			Path:   u.Path,
+			Query:  make(map[string]string),
+		}
+		if u.Opaque != "" {
+			link.Path = u.Opaque // 例如 myapp:open
+		}
+		for key, values := range u.Query() {
+			link.Query[key] = values[0]
+		}
+		links = append(links, link)
+	}
+	return links
+}
+
+func dispatchDeepLink(link DeepLink) {
+	deepLinkMutex.Lock()
+	handler := deepLinkHandler
+	windows := Windows()
+	if len(windows) == 0 {
+		deepLinkPending = append(deepLinkPending, link)
+	}
+	var ready []*Webview
+	for _, w := range windows {
+		q := deepLinkQueueLocked(w)
+		if q.ready {
+			ready = append(ready, w)
+		} else {
+			q.pending = append(q.pending, link)
+		}
+	}
+	deepLinkMutex.Unlock()
+
+	for _, w := range ready {
+		_ = w.Emit("deeplink", link)
+	}
+	if handler == nil {
+		return
+	}
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				slog.Error("Panic in deep link handler", "url", link.URL, "panic", r)
+			}
+		}()
+		handler(link)
+	}()
+}
+
+// installDeepLinkDelivery 接管没有窗口时收到的链接，并在窗口页面每次就绪后补发排队的链接
+func (w *Webview) installDeepLinkDelivery() {
+	deepLinkMutex.Lock()
+	q := deepLinkQueueLocked(w)
+	q.pending = append(q.pending, deepLinkPending...)
+	deepLinkPending = nil
+	deepLinkMutex.Unlock()
+
+	// 导航开始后旧页面的 runtime.js 即将失效，新链接需等到新页面就绪
+	w.On(EventNavigationStarted, func(wv *Webview, _ Event) {
+		deepLinkMutex.Lock()
+		deepLinkQueueLocked(wv).ready = false
+		deepLinkMutex.Unlock()
+	})
+	w.On(EventDomReady, func(wv *Webview, _ Event) {
+		deepLinkMutex.Lock()
+		q := deepLinkQueueLocked(wv)
+		q.ready = true
+		pending := q.pending
+		q.pending = nil
+		deepLinkMutex.Unlock()
+		for _, link := range pending {
+			_ = wv.Emit("deeplink", link)
+		}
+	})
+}
+
+// releaseDeepLinks 窗口关闭时丢弃其链接队列
+func releaseDeepLinks(w *Webview) {
+	deepLinkMutex.Lock()
+	delete(deepLinkWindows, w)
+	deepLinkMutex.Unlock()
+}
+
+// cOpenURLHandler 原生库通过系统事件（例如 macOS 的 Apple Event）收到 URL 时回调
+func cOpenURLHandler(urlPtr uintptr) uintptr {
+	HandleDeepLinkArgs([]string{goString(urlPtr)})
+	return 0
+}
+
+func installOpenURLCallback() {
+	if loadWebviewLibrary() != nil || webviewSetOpenURLCallback == nil {
+		return
+	}
+	deepLinkOnce.Do(func() {
+		deepLinkCallback = purego.NewCallback(cOpenURLHandler)
+		mainScheduler.RunInMainThread(func() { webviewSetOpenURLCallback(deepLinkCallback) })
+	})
+}
+
+// RegisterDeepLinkHandler 将当前程序注册为 Schemes 的系统处理程序：
+// Linux 安装带 x-scheme-handler MimeType 的 .desktop 文件并设为默认处理程序，
+// Windows 写入当前用户的注册表；macOS 需要在 Info.plist 的 CFBundleURLTypes 中声明。
+func RegisterDeepLinkHandler(opts DeepLinkOptions) error {
+	if len(opts.Schemes) == 0 {
+		return fmt.Errorf("webview: no URL schemes to register")
+	}
+	opts.Schemes = append([]string(nil), opts.Schemes...)
+	for i, scheme := range opts.Schemes {
+		opts.Schemes[i] = strings.ToLower(scheme)
+		if err := validateScheme(opts.Schemes[i]); err != nil {
+			return err
+		}
+	}
+	if opts.Exec == "" {
+		exe, err := os.Executable()
+		if err != nil {
+			return err
+		}
+		opts.Exec = exe
+	}
+	if opts.Name == "" {
+		opts.Name = applicationName()
+	}
+	if opts.DesktopID == "" {
+		opts.DesktopID = applicationName()
+	}
+	if !validAppID.MatchString(opts.DesktopID) {
+		return fmt.Errorf("webview: invalid desktop ID %q", opts.DesktopID)
+	}
+	switch runtime.GOOS {
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return registerDesktopEntry(opts)
+	case "windows":
+		return registerWindowsURLHandler(opts)
+	}
+	return fmt.Errorf("webview: URL schemes must be declared in the app bundle's Info.plist (CFBundleURLTypes) on %s", runtime.GOOS)
+}
+
+// desktopApplicationsDir $XDG_DATA_HOME/applications，默认 ~/.local/share/applications
+func desktopApplicationsDir() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "applications"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "share", "applications"), nil
+}
+
+func registerDesktopEntry(opts DeepLinkOptions) error {
+	dir, err := desktopApplicationsDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	file := opts.DesktopID + ".desktop"
+	if err := os.WriteFile(filepath.Join(dir, file), []byte(desktopEntry(opts)), 0o644); err != nil {
+		return err
+	}
+	for _, scheme := range opts.Schemes {
+		if err := runCommand("xdg-mime", "default", file, "x-scheme-handler/"+scheme); err != nil {
+			return fmt.Errorf("webview: xdg-mime failed for %s: %w", scheme, err)
+		}
+	}
+	// 刷新 MIME 缓存失败不影响 xdg-mime 已写入的默认处理程序
+	if err := runCommand("update-desktop-database", dir); err != nil {
+		slog.Debug("update-desktop-database failed", "error", err)
+	}
+	return nil
+}
+
+// desktopEntry 生成 .desktop 文件内容
+func desktopEntry(opts DeepLinkOptions) string {
+	var mime strings.Builder
+	for _, scheme := range opts.Schemes {
+		mime.WriteString("x-scheme-handler/" + scheme + ";")
+	}
+	var b strings.Builder
+	b.WriteString("[Desktop Entry]\n")
+	b.WriteString("Type=Application\n")
+	b.WriteString("Name=" + desktopEscape(opts.Name) + "\n")
+	b.WriteString("Exec=" + desktopExecQuote(opts.Exec) + " %u\n")
+	if opts.Icon != "" {
+		b.WriteString("Icon=" + desktopEscape(opts.Icon) + "\n")
+	}
+	b.WriteString("Terminal=false\n")
+	b.WriteString("MimeType=" + mime.String() + "\n")
+	return b.String()
+}
+
+// desktopEscape 转义 .desktop 字符串值中的换行与反斜杠
+func desktopEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`, "\t", `\t`, "\r", `\r`).Replace(s)
+}
+
+// desktopExecQuote 按 Desktop Entry 规范给 Exec 中的程序路径加引号，字面量 % 写作 %%
+func desktopExecQuote(path string) string {
+	quoted := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "`", "\\`", `$`, `\$`, `%`, `%%`).Replace(path)
+	quoted = `"` + quoted + `"`
+	// Exec 的值本身还要经过一次字符串转义
+	return strings.ReplaceAll(quoted, `\`, `\\`)
+}
+
+func registerWindowsURLHandler(opts DeepLinkOptions) error {
+	for _, scheme := range opts.Schemes {
+		key := `HKCU\Software\Classes\` + scheme
+		command := fmt.Sprintf(`"%s" "%%1"`, opts.Exec)
+		for _, args := range [][]string{
+			{"add", key, "/ve", "/d", "URL:" + opts.Name, "/f"},
+			{"add", key, "/v", "URL Protocol", "/d", "", "/f"},
+			{"add", key + `\shell\open\command`, "/ve", "/d", command, "/f"},
+		} {
+			if err := runCommand("reg", args...); err != nil {
+				return fmt.Errorf("webview: registering %s failed: %w", scheme, err)
+			}
+		}
+	}
+	return nil
+}
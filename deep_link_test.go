@@ -0,0 +1,201 @@
+package wvapp
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseDeepLinks(t *testing.T) {
+	if err := OnDeepLink([]string{"myapp", "MyApp-Beta"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	defer OnDeepLink(nil, nil)
+
+	links := parseDeepLinks([]string{
+		"--verbose",
+		"/home/u/report.pdf",
+		"https://example.com",
+		"other://open",
+		"myapp://open/doc?doc=123&doc=9&mode=edit",
+		"MYAPP-BETA://settings",
+		"myapp:about",
+	})
+	want := []DeepLink{
+		{URL: "myapp://open/doc?doc=123&doc=9&mode=edit", Scheme: "myapp", Host: "open", Path: "/doc", Query: map[string]string{"doc": "123", "mode": "edit"}},
+		{URL: "MYAPP-BETA://settings", Scheme: "myapp-beta", Host: "settings", Query: map[string]string{}},
+		{URL: "myapp:about", Scheme: "myapp", Path: "about", Query: map[string]string{}},
+	}
+	if !reflect.DeepEqual(links, want) {
+		t.Fatalf("parseDeepLinks =\n%+v\nwant\n%+v", links, want)
+	}
+}
+
+func TestOnDeepLinkRejectsSchemes(t *testing.T) {
+	for _, scheme := range []string{"https", "file", "javascript", "1app", "my app", ""} {
+		if err := OnDeepLink([]string{scheme}, nil); err == nil {
+			t.Errorf("scheme %q should be rejected", scheme)
+		}
+	}
+}
+
+func TestHandleDeepLinkArgs(t *testing.T) {
+	got := make(chan DeepLink, 1)
+	if err := OnDeepLink([]string{"myapp"}, func(link DeepLink) { got <- link }); err != nil {
+		t.Fatal(err)
+	}
+	defer OnDeepLink(nil, nil)
+	defer func() { deepLinkPending = nil }()
+
+	if HandleDeepLinkArgs([]string{"--flag"}) {
+		t.Fatal("no deep link expected")
+	}
+	if !HandleDeepLinkArgs([]string{"myapp://open?doc=123"}) {
+		t.Fatal("deep link not found")
+	}
+	select {
+	case link := <-got:
+		if link.Host != "open" || link.Query["doc"] != "123" {
+			t.Fatalf("link = %+v", link)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("handler not called")
+	}
+
+	// 没有窗口时链接会保留，等第一个页面就绪后发送
+	deepLinkMutex.Lock()
+	pending := len(deepLinkPending)
+	deepLinkMutex.Unlock()
+	if pending != 1 {
+		t.Fatalf("pending links = %d, want 1", pending)
+	}
+}
+
+// queuedDeepLinks 返回窗口队列中等待发送的链接
+func queuedDeepLinks(w *Webview) []string {
+	deepLinkMutex.Lock()
+	defer deepLinkMutex.Unlock()
+	var urls []string
+	if q := deepLinkWindows[w]; q != nil {
+		for _, link := range q.pending {
+			urls = append(urls, link.URL)
+		}
+	}
+	return urls
+}
+
+func TestDeepLinkQueuedUntilDomReady(t *testing.T) {
+	if err := OnDeepLink([]string{"myapp"}, nil); err != nil {
+		t.Fatal(err)
+	}
+	defer OnDeepLink(nil, nil)
+	// 只统计发给页面的脚本数量，链接内容通过队列检查
+	emitted := 0
+	original := webviewEvalJS
+	defer func() { webviewEvalJS = original }()
+	webviewEvalJS = func(*Webview, uintptr) { emitted++ }
+	mainScheduler.PollTasks()
+	emits := func() int {
+		mainScheduler.PollTasks()
+		return emitted
+	}
+
+	// 没有窗口时收到的链接交给下一个创建的窗口
+	HandleDeepLinkArgs([]string{"myapp://early"})
+	first, second := fakeWebview(), fakeWebview()
+	registerWindow(first, "")
+	defer releaseWindow(first)
+	first.installDeepLinkDelivery()
+	registerWindow(second, "")
+	defer releaseWindow(second)
+	second.installDeepLinkDelivery()
+
+	HandleDeepLinkArgs([]string{"myapp://open"})
+	if n := emits(); n != 0 {
+		t.Fatalf("links must wait for DomReady, emitted %d", n)
+	}
+	if got := queuedDeepLinks(first); !reflect.DeepEqual(got, []string{"myapp://early", "myapp://open"}) {
+		t.Fatalf("first window queue = %q", got)
+	}
+	if got := queuedDeepLinks(second); !reflect.DeepEqual(got, []string{"myapp://open"}) {
+		t.Fatalf("second window queue = %q", got)
+	}
+
+	second.dispatchEvent(DomReadyEvent{})
+	if n := emits(); n != 1 || len(queuedDeepLinks(first)) != 2 {
+		t.Fatalf("second window should only drain its own queue, emitted %d", n)
+	}
+	first.dispatchEvent(DomReadyEvent{})
+	if n := emits(); n != 3 || len(queuedDeepLinks(first)) != 0 {
+		t.Fatalf("first window should receive the early and queued links, emitted %d", n)
+	}
+
+	// 就绪的窗口立即收到新链接，导航开始后重新排队
+	HandleDeepLinkArgs([]string{"myapp://now"})
+	if n := emits(); n != 5 {
+		t.Fatalf("ready windows should receive links immediately, emitted %d", n)
+	}
+	first.dispatchEvent(NavigationStartedEvent{URL: "wvapp://index.html/"})
+	HandleDeepLinkArgs([]string{"myapp://later"})
+	if n := emits(); n != 6 {
+		t.Fatalf("navigating window should queue links, emitted %d", n)
+	}
+	if got := queuedDeepLinks(first); !reflect.DeepEqual(got, []string{"myapp://later"}) {
+		t.Fatalf("first window queue = %q", got)
+	}
+	first.dispatchEvent(DomReadyEvent{})
+	if n := emits(); n != 7 || len(queuedDeepLinks(first)) != 0 {
+		t.Fatalf("queued link not delivered after navigation, emitted %d", n)
+	}
+}
+
+func TestDesktopEntry(t *testing.T) {
+	entry := desktopEntry(DeepLinkOptions{
+		Schemes: []string{"myapp", "myapp-beta"},
+		Name:    "My App",
+		Exec:    `/opt/My App/bin/my$app%1`,
+		Icon:    "myapp",
+	})
+	want := "[Desktop Entry]\n" +
+		"Type=Application\n" +
+		"Name=My App\n" +
+		`Exec="/opt/My App/bin/my\\$app%%1" %u` + "\n" +
+		"Icon=myapp\n" +
+		"Terminal=false\n" +
+		"MimeType=x-scheme-handler/myapp;x-scheme-handler/myapp-beta;\n"
+	if entry != want {
+		t.Fatalf("desktopEntry =\n%s\nwant\n%s", entry, want)
+	}
+}
+
+func TestRegisterDesktopEntry(t *testing.T) {
+	dataHome := t.TempDir()
+	t.Setenv("XDG_DATA_HOME", dataHome)
+	var commands []string
+	original := runCommand
+	defer func() { runCommand = original }()
+	runCommand = func(name string, args ...string) error {
+		commands = append(commands, name+" "+strings.Join(args, " "))
+		return nil
+	}
+
+	err := registerDesktopEntry(DeepLinkOptions{Schemes: []string{"myapp"}, Name: "My App", Exec: "/usr/bin/myapp", DesktopID: "com.example.myapp"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(dataHome, "applications")
+	data, err := os.ReadFile(filepath.Join(dir, "com.example.myapp.desktop"))
+	if err != nil || !strings.Contains(string(data), "MimeType=x-scheme-handler/myapp;") {
+		t.Fatalf("desktop file = %q, %v", data, err)
+	}
+	wantCommands := []string{
+		"xdg-mime default com.example.myapp.desktop x-scheme-handler/myapp",
+		"update-desktop-database " + dir,
+	}
+	if !reflect.DeepEqual(commands, wantCommands) {
+		t.Fatalf("commands = %q", commands)
+	}
+}
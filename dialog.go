@@ -0,0 +1,363 @@
+package wvapp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"runtime"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ebitengine/purego"
+	"github.com/millken/goid"
+)
+
+// FileFilter 文件类型过滤器，例如 {Name: "Images", Patterns: []string{"*.png", "*.jpg"}}
+type FileFilter struct {
+	Name     string   `json:"name"`
+	Patterns []string `json:"patterns"`
+}
+
+// FileDialogOptions 文件对话框选项
+type FileDialogOptions struct {
+	Title       string       `json:"title,omitempty"`
+	DefaultPath string       `json:"defaultPath,omitempty"` // 初始目录或文件（保存对话框中作为默认文件名）
+	Filters     []FileFilter `json:"filters,omitempty"`
+	MultiSelect bool         `json:"multiSelect,omitempty"` // 仅对 OpenFileDialog 有效
+	ShowHidden  bool         `json:"showHidden,omitempty"`
+	Parent      *Webview     `json:"-"` // 对话框所属窗口（nil 表示无父窗口）
+}
+
+// MessageKind 消息框图标类型
+type MessageKind int
+
+const (
+	MessageInfo MessageKind = iota
+	MessageWarning
+	MessageError
+	MessageQuestion
+)
+
+// MessageDialogOptions 消息框选项
+//
+// Buttons 为空时 MessageQuestion 使用 "Yes"/"No"，其他类型使用 "OK"。
+// DefaultButton、CancelButton 为按钮文字，CancelButton 是按 Esc 或关闭对话框时返回的按钮。
+type MessageDialogOptions struct {
+	Kind          MessageKind `json:"kind"`
+	Title         string      `json:"title,omitempty"`
+	Message       string      `json:"message"`
+	Detail        string      `json:"detail,omitempty"`
+	Buttons       []string    `json:"buttons,omitempty"`
+	DefaultButton string      `json:"defaultButton,omitempty"`
+	CancelButton  string      `json:"cancelButton,omitempty"`
+	Parent        *Webview    `json:"-"` // 对话框所属窗口（nil 表示无父窗口）
+}
+
+// DialogBackend 对话框后端，测试中可用 SetDialogBackend 替换为脚本化的实现
+//
+// 用户取消时返回空结果与 nil 错误，ctx 结束时放弃等待并返回 ctx.Err()。Message 返回所选按钮的文字。
+type DialogBackend interface {
+	OpenFile(ctx context.Context, opts FileDialogOptions) ([]string, error)
+	SaveFile(ctx context.Context, opts FileDialogOptions) (string, error)
+	SelectDirectory(ctx context.Context, opts FileDialogOptions) (string, error)
+	Message(ctx context.Context, opts MessageDialogOptions) (string, error)
+}
+
+// ErrMainThreadBlocked 在主线程上调用会阻塞事件循环的同步 API 时返回
+var ErrMainThreadBlocked = errors.New("webview: blocking dialog called on the main thread, call it from another goroutine")
+
+const (
+	fileDialogOpen int32 = iota
+	fileDialogSave
+	fileDialogDirectory
+)
+
+var (
+	dialogBackend      DialogBackend = nativeDialogBackend{}
+	dialogBackendMutex sync.RWMutex
+)
+
+// SetDialogBackend 替换对话框后端，传入 nil 恢复原生实现
+func SetDialogBackend(backend DialogBackend) {
+	if backend == nil {
+		backend = nativeDialogBackend{}
+	}
+	dialogBackendMutex.Lock()
+	dialogBackend = backend
+	dialogBackendMutex.Unlock()
+}
+
+func currentDialogBackend() DialogBackend {
+	dialogBackendMutex.RLock()
+	defer dialogBackendMutex.RUnlock()
+	return dialogBackend
+}
+
+// OpenFileDialog 打开文件选择对话框，返回选中的文件路径（取消时为空）；ctx 结束时不再等待并返回 ctx.Err()
+//
+// 与 MessageDialog 一样只阻塞调用方 goroutine，在主线程上调用返回 ErrMainThreadBlocked。
+func OpenFileDialog(ctx context.Context, opts FileDialogOptions) ([]string, error) {
+	if isMainThread() {
+		return nil, ErrMainThreadBlocked
+	}
+	return currentDialogBackend().OpenFile(ctx, opts)
+}
+
+// SaveFileDialog 打开保存对话框，返回目标路径（取消时为空字符串）
+func SaveFileDialog(ctx context.Context, opts FileDialogOptions) (string, error) {
+	if isMainThread() {
+		return "", ErrMainThreadBlocked
+	}
+	return currentDialogBackend().SaveFile(ctx, opts)
+}
+
+// SelectDirectoryDialog 打开目录选择对话框，返回目录路径（取消时为空字符串）
+func SelectDirectoryDialog(ctx context.Context, opts FileDialogOptions) (string, error) {
+	if isMainThread() {
+		return "", ErrMainThreadBlocked
+	}
+	return currentDialogBackend().SelectDirectory(ctx, opts)
+}
+
+// MessageDialog 显示消息框并等待用户选择，返回所选按钮的文字；ctx 结束时不再等待并返回 ctx.Err()
+//
+// 调用方 goroutine 会阻塞，但主线程不会：可以在 HandlerFunc（工作池）中直接调用。
+// 在主线程上（例如事件回调中）请使用 MessageDialogAsync。
+func MessageDialog(ctx context.Context, opts MessageDialogOptions) (string, error) {
+	if isMainThread() {
+		return "", ErrMainThreadBlocked
+	}
+	return currentDialogBackend().Message(ctx, normalizeMessageOptions(opts))
+}
+
+// MessageDialogAsync 显示消息框并立即返回，用户选择或 ctx 结束后在新的 goroutine 中调用 callback
+func MessageDialogAsync(ctx context.Context, opts MessageDialogOptions, callback func(button string, err error)) {
+	opts = normalizeMessageOptions(opts)
+	backend := currentDialogBackend()
+	go func() {
+		button, err := backend.Message(ctx, opts)
+		if callback != nil {
+			callback(button, err)
+		}
+	}()
+}
+
+func normalizeMessageOptions(opts MessageDialogOptions) MessageDialogOptions {
+	if len(opts.Buttons) == 0 {
+		if opts.Kind == MessageQuestion {
+			opts.Buttons = []string{"Yes", "No"}
+		} else {
+			opts.Buttons = []string{"OK"}
+		}
+	} else {
+		opts.Buttons = append([]string(nil), opts.Buttons...)
+	}
+	if opts.DefaultButton == "" {
+		opts.DefaultButton = opts.Buttons[0]
+	}
+	if opts.CancelButton == "" && len(opts.Buttons) == 1 {
+		opts.CancelButton = opts.Buttons[0]
+	}
+	return opts
+}
+
+func isMainThread() bool {
+	gid := atomic.LoadInt64(&mainGID)
+	return gid != 0 && goid.Goid() == gid
+}
+
+// nativeDialogBackend 通过原生库在主线程中显示对话框
+type nativeDialogBackend struct{}
+
+var (
+	fileDialogCallback     uintptr
+	fileDialogCallbackOnce sync.Once
+	fileDialogRequests     nativeRequests[dialogResult]
+)
+
+type dialogResult struct {
+	paths []string
+	err   error
+}
+
+// cFileDialogHandler 原生文件对话框关闭时回调，result 为 JSON 路径数组，用户取消时为 0
+func cFileDialogHandler(id uintptr, result uintptr) uintptr {
+	fileDialogRequests.resolve(id, parseDialogResult(goString(result)))
+	return 0
+}
+
+func parseDialogResult(data string) dialogResult {
+	if data == "" {
+		return dialogResult{} // 用户取消
+	}
+	var paths []string
+	if err := json.Unmarshal([]byte(data), &paths); err != nil {
+		return dialogResult{err: fmt.Errorf("webview: invalid dialog result: %w", err)}
+	}
+	return dialogResult{paths: paths}
+}
+
+// show 与 Message 相同，主线程任务只负责弹出对话框，结果通过回调送回等待的 goroutine。
+// 旧版本原生库只有同步的 webview_file_dialog，此时对话框在主线程任务中模态运行，
+// 但调用方仍可通过 ctx 放弃等待
+func (nativeDialogBackend) show(ctx context.Context, kind int32, opts FileDialogOptions) ([]string, error) {
+	if err := loadWebviewLibrary(); err != nil {
+		return nil, err
+	}
+	if webviewFileDialogAsync == nil && (webviewFileDialog == nil || webviewFreeString == nil) {
+		return nil, ErrNotSupported
+	}
+	optsJSON, err := json.Marshal(opts)
+	if err != nil {
+		return nil, err
+	}
+	fileDialogCallbackOnce.Do(func() { fileDialogCallback = purego.NewCallback(cFileDialogHandler) })
+
+	res, err := fileDialogRequests.do(ctx, func(id uintptr) {
+		cstr, ptr := cString(string(optsJSON))
+		defer runtime.KeepAlive(cstr)
+		if webviewFileDialogAsync != nil {
+			webviewFileDialogAsync(opts.Parent, kind, ptr, fileDialogCallback, id)
+			return
+		}
+		var data string
+		if resultPtr := webviewFileDialog(opts.Parent, kind, ptr); resultPtr != 0 {
+			data = goString(resultPtr)
+			webviewFreeString(resultPtr)
+		}
+		fileDialogRequests.resolve(id, parseDialogResult(data))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res.paths, res.err
+}
+
+func (b nativeDialogBackend) OpenFile(ctx context.Context, opts FileDialogOptions) ([]string, error) {
+	return b.show(ctx, fileDialogOpen, opts)
+}
+
+func (b nativeDialogBackend) SaveFile(ctx context.Context, opts FileDialogOptions) (string, error) {
+	paths, err := b.show(ctx, fileDialogSave, opts)
+	if err != nil || len(paths) == 0 {
+		return "", err
+	}
+	return paths[0], nil
+}
+
+func (b nativeDialogBackend) SelectDirectory(ctx context.Context, opts FileDialogOptions) (string, error) {
+	paths, err := b.show(ctx, fileDialogDirectory, opts)
+	if err != nil || len(paths) == 0 {
+		return "", err
+	}
+	return paths[0], nil
+}
+
+var (
+	messageDialogCallback     uintptr
+	messageDialogCallbackOnce sync.Once
+	messageDialogRequests     nativeRequests[int32]
+)
+
+// cMessageDialogHandler 原生消息框关闭时回调，button 为按钮索引，-1 表示未选择直接关闭
+func cMessageDialogHandler(id uintptr, button int32) uintptr {
+	messageDialogRequests.resolve(id, button)
+	return 0
+}
+
+// Message 原生消息框是异步的：主线程任务只负责弹出对话框，随后立即返回继续处理事件，
+// 结果通过回调送回等待的 goroutine，因此不会占住调度器
+func (nativeDialogBackend) Message(ctx context.Context, opts MessageDialogOptions) (string, error) {
+	if err := loadWebviewLibrary(); err != nil {
+		return "", err
+	}
+	if webviewMessageDialog == nil {
+		return "", ErrNotSupported
+	}
+	optsJSON, err := json.Marshal(opts)
+	if err != nil {
+		return "", err
+	}
+	messageDialogCallbackOnce.Do(func() { messageDialogCallback = purego.NewCallback(cMessageDialogHandler) })
+
+	button, err := messageDialogRequests.do(ctx, func(id uintptr) {
+		cstr, ptr := cString(string(optsJSON))
+		webviewMessageDialog(opts.Parent, ptr, messageDialogCallback, id)
+		runtime.KeepAlive(cstr)
+	})
+	if err != nil {
+		return "", err
+	}
+	return messageButton(opts, button), nil
+}
+
+// messageButton 将原生返回的按钮索引转换为按钮文字
+func messageButton(opts MessageDialogOptions, index int32) string {
+	if index < 0 || int(index) >= len(opts.Buttons) {
+		return opts.CancelButton
+	}
+	return opts.Buttons[index]
+}
+
+// windowDialogContext 为 JS 发起的对话框创建 ctx：等待用户操作不受工作池时限约束，
+// 但窗口关闭后不再等待。调用方需要调用返回的 cancel
+func windowDialogContext(ctx context.Context, wv *Webview) (context.Context, context.CancelFunc) {
+	dialogCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	unsubscribe := wv.On(EventClose, func(*Webview, Event) { cancel() })
+	return dialogCtx, func() {
+		unsubscribe()
+		cancel()
+	}
+}
+
+// fileDialogOptionsFromJS 将 JS 传入的选项对象转换为 FileDialogOptions
+func fileDialogOptionsFromJS(wv *Webview, args []any) (FileDialogOptions, error) {
+	var opts FileDialogOptions
+	if len(args) > 0 && args[0] != nil {
+		data, err := json.Marshal(args[0])
+		if err != nil {
+			return opts, err
+		}
+		if err := json.Unmarshal(data, &opts); err != nil {
+			return opts, fmt.Errorf("invalid dialog options: %w", err)
+		}
+	}
+	opts.Parent = wv
+	return opts, nil
+}
+
+// messageDialogOptionsFromJS 将 JS 传入的选项对象转换为 MessageDialogOptions，kind 可以是名称或数字
+func messageDialogOptionsFromJS(wv *Webview, args []any) (MessageDialogOptions, error) {
+	var opts MessageDialogOptions
+	if len(args) == 0 || args[0] == nil {
+		return opts, fmt.Errorf("missing dialog options")
+	}
+	m, ok := args[0].(map[string]any)
+	if !ok {
+		return opts, fmt.Errorf("invalid dialog options")
+	}
+	if name, ok := m["kind"].(string); ok {
+		kind, known := map[string]MessageKind{
+			"info":     MessageInfo,
+			"warning":  MessageWarning,
+			"error":    MessageError,
+			"question": MessageQuestion,
+		}[name]
+		if !known {
+			return opts, fmt.Errorf("unknown dialog kind %q", name)
+		}
+		m = maps.Clone(m)
+		m["kind"] = int(kind)
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return opts, err
+	}
+	if err := json.Unmarshal(data, &opts); err != nil {
+		return opts, fmt.Errorf("invalid dialog options: %w", err)
+	}
+	opts.Parent = wv
+	return opts, nil
+}
@@ -0,0 +1,309 @@
+package wvapp
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// scriptedDialogBackend 按调用顺序返回预设结果，并记录收到的选项
+type scriptedDialogBackend struct {
+	open     [][]string
+	save     []string
+	dir      []string
+	buttons  []string
+	calls    []FileDialogOptions
+	messages []MessageDialogOptions
+}
+
+func (b *scriptedDialogBackend) OpenFile(ctx context.Context, opts FileDialogOptions) ([]string, error) {
+	b.calls = append(b.calls, opts)
+	r := b.open[0]
+	b.open = b.open[1:]
+	return r, nil
+}
+
+func (b *scriptedDialogBackend) SaveFile(ctx context.Context, opts FileDialogOptions) (string, error) {
+	b.calls = append(b.calls, opts)
+	r := b.save[0]
+	b.save = b.save[1:]
+	return r, nil
+}
+
+func (b *scriptedDialogBackend) SelectDirectory(ctx context.Context, opts FileDialogOptions) (string, error) {
+	b.calls = append(b.calls, opts)
+	r := b.dir[0]
+	b.dir = b.dir[1:]
+	return r, nil
+}
+
+func (b *scriptedDialogBackend) Message(ctx context.Context, opts MessageDialogOptions) (string, error) {
+	b.messages = append(b.messages, opts)
+	r := b.buttons[0]
+	b.buttons = b.buttons[1:]
+	return r, nil
+}
+
+func TestScriptedDialogBackend(t *testing.T) {
+	backend := &scriptedDialogBackend{
+		open: [][]string{{"/tmp/a.png", "/tmp/b.png"}, nil},
+		save: []string{"/tmp/report.pdf"},
+		dir:  []string{"/tmp"},
+	}
+	SetDialogBackend(backend)
+	defer SetDialogBackend(nil)
+
+	paths, err := OpenFileDialog(context.Background(), FileDialogOptions{
+		Title:       "Pick images",
+		MultiSelect: true,
+		Filters:     []FileFilter{{Name: "Images", Patterns: []string{"*.png"}}},
+	})
+	if err != nil || !reflect.DeepEqual(paths, []string{"/tmp/a.png", "/tmp/b.png"}) {
+		t.Fatalf("OpenFileDialog = %v, %v", paths, err)
+	}
+	if paths, err := OpenFileDialog(context.Background(), FileDialogOptions{}); err != nil || paths != nil {
+		t.Fatalf("cancelled dialog should return no paths, got %v, %v", paths, err)
+	}
+	if path, err := SaveFileDialog(context.Background(), FileDialogOptions{DefaultPath: "report.pdf"}); err != nil || path != "/tmp/report.pdf" {
+		t.Fatalf("SaveFileDialog = %q, %v", path, err)
+	}
+	if path, err := SelectDirectoryDialog(context.Background(), FileDialogOptions{}); err != nil || path != "/tmp" {
+		t.Fatalf("SelectDirectoryDialog = %q, %v", path, err)
+	}
+	if backend.calls[0].Title != "Pick images" || !backend.calls[0].MultiSelect {
+		t.Fatalf("options not forwarded: %+v", backend.calls[0])
+	}
+}
+
+func TestDialogFromJS(t *testing.T) {
+	backend := &scriptedDialogBackend{open: [][]string{{"/home/user/doc.txt"}}, save: []string{""}}
+	SetDialogBackend(backend)
+	defer SetDialogBackend(nil)
+
+	wv := fakeWebview()
+	args := []any{map[string]any{
+		"title":       "Open",
+		"defaultPath": "/home/user",
+		"multiSelect": true,
+		"filters":     []any{map[string]any{"name": "Text", "patterns": []any{"*.txt"}}},
+	}}
+	result, err := UserFunctionRegistry["_go_runtime_dialogOpenFile"](context.Background(), wv, args)
+	if err != nil || !reflect.DeepEqual(result, []string{"/home/user/doc.txt"}) {
+		t.Fatalf("open from JS = %v, %v", result, err)
+	}
+	got := backend.calls[0]
+	want := FileDialogOptions{
+		Title:       "Open",
+		DefaultPath: "/home/user",
+		MultiSelect: true,
+		Filters:     []FileFilter{{Name: "Text", Patterns: []string{"*.txt"}}},
+		Parent:      wv,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("options from JS = %+v, want %+v", got, want)
+	}
+
+	result, err = UserFunctionRegistry["_go_runtime_dialogSaveFile"](context.Background(), wv, nil)
+	if err != nil || result != nil {
+		t.Fatalf("cancelled save from JS should resolve to null, got %v, %v", result, err)
+	}
+}
+
+func TestMessageDialogDefaults(t *testing.T) {
+	tests := []struct {
+		name string
+		opts MessageDialogOptions
+		want MessageDialogOptions
+	}{
+		{
+			name: "info",
+			opts: MessageDialogOptions{Message: "Saved"},
+			want: MessageDialogOptions{Message: "Saved", Buttons: []string{"OK"}, DefaultButton: "OK", CancelButton: "OK"},
+		},
+		{
+			name: "question",
+			opts: MessageDialogOptions{Kind: MessageQuestion, Message: "Continue?"},
+			want: MessageDialogOptions{Kind: MessageQuestion, Message: "Continue?", Buttons: []string{"Yes", "No"}, DefaultButton: "Yes"},
+		},
+		{
+			name: "custom",
+			opts: MessageDialogOptions{Kind: MessageWarning, Buttons: []string{"Delete", "Cancel"}, DefaultButton: "Cancel", CancelButton: "Cancel"},
+			want: MessageDialogOptions{Kind: MessageWarning, Buttons: []string{"Delete", "Cancel"}, DefaultButton: "Cancel", CancelButton: "Cancel"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeMessageOptions(tt.opts); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("normalizeMessageOptions = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	opts := normalizeMessageOptions(MessageDialogOptions{Buttons: []string{"Delete", "Cancel"}, CancelButton: "Cancel"})
+	if got := messageButton(opts, 0); got != "Delete" {
+		t.Fatalf("messageButton(0) = %q", got)
+	}
+	if got := messageButton(opts, -1); got != "Cancel" {
+		t.Fatalf("dismissed dialog should return the cancel button, got %q", got)
+	}
+}
+
+func TestMessageDialog(t *testing.T) {
+	backend := &scriptedDialogBackend{buttons: []string{"Delete", "Cancel"}}
+	SetDialogBackend(backend)
+	defer SetDialogBackend(nil)
+
+	button, err := MessageDialog(context.Background(), MessageDialogOptions{Kind: MessageWarning, Message: "Delete file?", Buttons: []string{"Delete", "Cancel"}})
+	if err != nil || button != "Delete" {
+		t.Fatalf("MessageDialog = %q, %v", button, err)
+	}
+
+	done := make(chan string, 1)
+	MessageDialogAsync(context.Background(), MessageDialogOptions{Message: "Delete file?"}, func(button string, err error) {
+		done <- button
+	})
+	if got := <-done; got != "Cancel" {
+		t.Fatalf("MessageDialogAsync = %q", got)
+	}
+	if backend.messages[1].DefaultButton != "OK" {
+		t.Fatalf("async options not normalized: %+v", backend.messages[1])
+	}
+}
+
+func TestMessageDialogFromJS(t *testing.T) {
+	backend := &scriptedDialogBackend{buttons: []string{"No"}}
+	SetDialogBackend(backend)
+	defer SetDialogBackend(nil)
+
+	wv := fakeWebview()
+	args := []any{map[string]any{"kind": "question", "title": "Quit", "message": "Quit now?"}}
+	result, err := UserFunctionRegistry["_go_runtime_dialogMessage"](context.Background(), wv, args)
+	if err != nil || result != "No" {
+		t.Fatalf("message from JS = %v, %v", result, err)
+	}
+	got := backend.messages[0]
+	if got.Kind != MessageQuestion || got.Title != "Quit" || got.Parent != wv || len(got.Buttons) != 2 {
+		t.Fatalf("options from JS = %+v", got)
+	}
+
+	if _, err := messageDialogOptionsFromJS(wv, []any{map[string]any{"kind": "fatal"}}); err == nil {
+		t.Fatal("unknown kind should be rejected")
+	}
+	if _, err := messageDialogOptionsFromJS(wv, nil); err == nil {
+		t.Fatal("missing options should be rejected")
+	}
+}
+
+func TestNativeMessageDialogCallback(t *testing.T) {
+	id, ch := messageDialogRequests.add()
+	cMessageDialogHandler(id, 1)
+	if got := <-ch; got != 1 {
+		t.Fatalf("callback delivered %d", got)
+	}
+	// 重复回调不应阻塞或 panic
+	cMessageDialogHandler(id, 0)
+}
+
+// waitingDialogBackend 对话框一直不返回，直到 ctx 结束
+type waitingDialogBackend struct {
+	scriptedDialogBackend
+	shown chan struct{}
+}
+
+func (b *waitingDialogBackend) SaveFile(ctx context.Context, opts FileDialogOptions) (string, error) {
+	close(b.shown)
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+func (b *waitingDialogBackend) Message(ctx context.Context, opts MessageDialogOptions) (string, error) {
+	close(b.shown)
+	<-ctx.Done()
+	return "", ctx.Err()
+}
+
+func TestMessageDialogFromJSCanceledOnClose(t *testing.T) {
+	backend := &waitingDialogBackend{shown: make(chan struct{})}
+	SetDialogBackend(backend)
+	defer SetDialogBackend(nil)
+
+	wv := fakeWebview()
+	defer releaseWindow(wv)
+	// 工作池的时限不应打断等待用户操作的消息框
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		_, err := UserFunctionRegistry["_go_runtime_dialogMessage"](ctx, wv, []any{map[string]any{"message": "Quit?"}})
+		done <- err
+	}()
+	<-backend.shown
+	select {
+	case err := <-done:
+		t.Fatalf("dialog returned before the window closed: %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	wv.dispatchEvent(CloseEvent{})
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Fatalf("error = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("closing the window did not stop the dialog wait")
+	}
+}
+
+func TestSaveFileDialogFromJSCanceledOnClose(t *testing.T) {
+	backend := &waitingDialogBackend{shown: make(chan struct{})}
+	SetDialogBackend(backend)
+	defer SetDialogBackend(nil)
+
+	wv := fakeWebview()
+	defer releaseWindow(wv)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		_, err := UserFunctionRegistry["_go_runtime_dialogSaveFile"](ctx, wv, nil)
+		done <- err
+	}()
+	<-backend.shown
+	select {
+	case err := <-done:
+		t.Fatalf("dialog returned before the window closed: %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	wv.dispatchEvent(CloseEvent{})
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Fatalf("error = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("closing the window did not stop the dialog wait")
+	}
+}
+
+func TestFileDialogCallback(t *testing.T) {
+	if res := parseDialogResult(`["/tmp/a.txt","/tmp/b.txt"]`); res.err != nil || !reflect.DeepEqual(res.paths, []string{"/tmp/a.txt", "/tmp/b.txt"}) {
+		t.Fatalf("parseDialogResult = %+v", res)
+	}
+	if res := parseDialogResult(""); res.err != nil || res.paths != nil {
+		t.Fatalf("canceled dialog = %+v", res)
+	}
+	if res := parseDialogResult("{"); res.err == nil {
+		t.Fatal("invalid result should return an error")
+	}
+
+	id, ch := fileDialogRequests.add()
+	cFileDialogHandler(id, 0)
+	if res := <-ch; res.err != nil || res.paths != nil {
+		t.Fatalf("canceled callback delivered %+v", res)
+	}
+	// 重复回调不应阻塞或 panic
+	cFileDialogHandler(id, 0)
+}
@@ -0,0 +1,472 @@
+package wvapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ebitengine/purego"
+)
+
+// DownloadState 下载状态
+type DownloadState int
+
+const (
+	DownloadInProgress DownloadState = iota
+	DownloadCompleted
+	DownloadCanceled
+	DownloadFailed
+)
+
+var downloadStateNames = map[DownloadState]string{
+	DownloadInProgress: "progressing",
+	DownloadCompleted:  "completed",
+	DownloadCanceled:   "canceled",
+	DownloadFailed:     "failed",
+}
+
+func (s DownloadState) String() string {
+	if name, ok := downloadStateNames[s]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// parseDownloadState 解析原生库传来的状态名称，未知名称视为失败
+func parseDownloadState(name string) DownloadState {
+	for state, n := range downloadStateNames {
+		if n == name {
+			return state
+		}
+	}
+	return DownloadFailed
+}
+
+// Download 一次下载，OnDownloadStarted 回调与下载事件中传递同一个实例
+type Download struct {
+	ID            uint64
+	URL           string
+	SuggestedName string // 服务器建议的文件名，已去除路径部分
+	MIMEType      string
+
+	window *Webview
+
+	mu       sync.Mutex
+	path     string
+	received int64
+	total    int64 // -1 表示未知
+	state    DownloadState
+	errMsg   string
+}
+
+// Path 保存路径，下载开始前为空
+func (d *Download) Path() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.path
+}
+
+// Progress 已接收字节数与总字节数，总大小未知时 total 为 -1
+func (d *Download) Progress() (received, total int64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.received, d.total
+}
+
+// State 当前状态
+func (d *Download) State() DownloadState {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.state
+}
+
+// Err 下载失败时的错误描述
+func (d *Download) Err() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.errMsg
+}
+
+// Cancel 取消进行中的下载，结果以 DownloadCanceled 状态的 EventDownloadFinished 事件通知
+func (d *Download) Cancel() error {
+	if d.State() != DownloadInProgress {
+		return nil
+	}
+	if webviewDownloadCancel == nil {
+		return ErrNotSupported
+	}
+	mainScheduler.RunInMainThread(func() { webviewDownloadCancel(d.window, uintptr(d.ID)) })
+	return nil
+}
+
+// downloadInfo 发送给页面的下载数据
+type downloadInfo struct {
+	ID            uint64 `json:"id"`
+	URL           string `json:"url"`
+	SuggestedName string `json:"suggestedName"`
+	MIMEType      string `json:"mimeType"`
+	Path          string `json:"path"`
+	Received      int64  `json:"received"`
+	Total         int64  `json:"total"`
+	State         string `json:"state"`
+	Error         string `json:"error,omitempty"`
+}
+
+func (d *Download) info() downloadInfo {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return downloadInfo{
+		ID:            d.ID,
+		URL:           d.URL,
+		SuggestedName: d.SuggestedName,
+		MIMEType:      d.MIMEType,
+		Path:          d.path,
+		Received:      d.received,
+		Total:         d.total,
+		State:         d.state.String(),
+		Error:         d.errMsg,
+	}
+}
+
+// pageInfo 发送给窗口页面的下载数据，只有应用自身（或策略允许）的页面能看到本地保存路径
+func (d *Download) pageInfo(w *Webview) downloadInfo {
+	info := d.info()
+	if !w.trustedPage() {
+		info.Path = ""
+	}
+	return info
+}
+
+// DownloadDecision OnDownloadStarted 的返回值
+type DownloadDecision struct {
+	Cancel bool   // 不下载
+	Path   string // 保存路径；为已存在的目录时使用建议的文件名；为空时保存到用户下载目录
+}
+
+// DownloadHandler 下载开始回调，在主线程中同步调用，不应执行耗时操作
+type DownloadHandler func(d *Download) DownloadDecision
+
+var (
+	downloadHandlers     = make(map[*Webview]DownloadHandler)
+	downloadRegistry     = make(map[uint64]*Download)
+	downloadMutex        sync.Mutex
+	downloadCallback     uintptr
+	downloadCallbackOnce sync.Once
+)
+
+// OnDownloadStarted 设置窗口的下载回调，nil 恢复默认行为（保存到用户下载目录，重名时自动改名）
+func (w *Webview) OnDownloadStarted(handler DownloadHandler) {
+	downloadMutex.Lock()
+	defer downloadMutex.Unlock()
+	if handler == nil {
+		delete(downloadHandlers, w)
+		return
+	}
+	downloadHandlers[w] = handler
+}
+
+// OnDownloadProgress 订阅下载进度，返回取消订阅函数
+func (w *Webview) OnDownloadProgress(fn func(d *Download)) (unsubscribe func()) {
+	if fn == nil {
+		return func() {}
+	}
+	return w.On(EventDownloadProgress, func(_ *Webview, ev Event) { fn(ev.(DownloadProgressEvent).Download) })
+}
+
+// OnDownloadFinished 订阅下载完成、取消或失败，返回取消订阅函数
+func (w *Webview) OnDownloadFinished(fn func(d *Download)) (unsubscribe func()) {
+	if fn == nil {
+		return func() {}
+	}
+	return w.On(EventDownloadFinished, func(_ *Webview, ev Event) { fn(ev.(DownloadFinishedEvent).Download) })
+}
+
+// Downloads 返回窗口中进行中的下载
+func (w *Webview) Downloads() []*Download {
+	downloadMutex.Lock()
+	defer downloadMutex.Unlock()
+	var list []*Download
+	for _, d := range downloadRegistry {
+		if d.window == w {
+			list = append(list, d)
+		}
+	}
+	return list
+}
+
+// lookupDownload 按 ID 查找进行中的下载
+func lookupDownload(id uint64) *Download {
+	downloadMutex.Lock()
+	defer downloadMutex.Unlock()
+	return downloadRegistry[id]
+}
+
+// nativeDownloadRequest 原生库在下载开始时传入的 JSON
+type nativeDownloadRequest struct {
+	URL           string `json:"url"`
+	SuggestedName string `json:"suggestedName"`
+	MIMEType      string `json:"mimeType"`
+	Total         int64  `json:"total"`
+}
+
+// cDownloadHandler 原生下载开始回调：返回 0 取消下载；返回 1 前已通过
+// webview_download_set_destination 设置保存路径
+func cDownloadHandler(wv *Webview, id uintptr, infoPtr uintptr) uintptr {
+	req := nativeDownloadRequest{Total: -1}
+	if err := json.Unmarshal([]byte(goString(infoPtr)), &req); err != nil {
+		slog.Warn("Canceling download with invalid request", "error", err)
+		return 0
+	}
+	d := &Download{
+		ID:            uint64(id),
+		URL:           req.URL,
+		SuggestedName: sanitizeDownloadName(req.SuggestedName),
+		MIMEType:      req.MIMEType,
+		window:        wv,
+		total:         req.Total,
+	}
+	path, ok := wv.decideDownload(d)
+	if !ok {
+		slog.Debug("Download canceled", "url", d.URL)
+		return 0
+	}
+	d.path = path
+
+	// 下载回调都在主线程中依次执行，登记之后下一个下载才会选择路径，因此不会选中同一个文件
+	downloadMutex.Lock()
+	downloadRegistry[d.ID] = d
+	downloadMutex.Unlock()
+
+	cstr, ptr := cString(path)
+	webviewDownloadSetDestination(wv, id, ptr)
+	runtime.KeepAlive(cstr)
+	_ = wv.Emit("downloadstarted", d.pageInfo(wv))
+	return 1
+}
+
+// decideDownload 调用窗口的下载回调并确定保存路径，返回 false 表示取消
+func (w *Webview) decideDownload(d *Download) (string, bool) {
+	downloadMutex.Lock()
+	handler := downloadHandlers[w]
+	downloadMutex.Unlock()
+
+	var decision DownloadDecision
+	if handler != nil {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					slog.Error("Panic in download handler", "url", d.URL, "panic", r)
+					decision = DownloadDecision{Cancel: true}
+				}
+			}()
+			decision = handler(d)
+		}()
+	}
+	if decision.Cancel {
+		return "", false
+	}
+	path, err := downloadDestination(decision.Path, d.SuggestedName)
+	if err != nil {
+		slog.Error("Download canceled", "url", d.URL, "error", err)
+		return "", false
+	}
+	return path, true
+}
+
+// downloadDestination 根据回调给出的路径计算最终保存位置；
+// 保存到目录时（包括默认下载目录）遇到同名文件自动改名，显式给出的文件路径会被覆盖
+func downloadDestination(path, suggested string) (string, error) {
+	if path == "" {
+		dir, err := userDownloadsDir()
+		if err != nil {
+			return "", err
+		}
+		path = dir
+	}
+	if !filepath.IsAbs(path) {
+		return "", fmt.Errorf("webview: download path %q is not absolute", path)
+	}
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		return uniqueDownloadPath(filepath.Join(path, suggested)), nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("webview: cannot create download directory: %w", err)
+	}
+	return path, nil
+}
+
+// userDownloadsDir 用户下载目录：$XDG_DOWNLOAD_DIR、user-dirs.dirs 中的 XDG_DOWNLOAD_DIR，默认 ~/Downloads
+func userDownloadsDir() (string, error) {
+	if dir := os.Getenv("XDG_DOWNLOAD_DIR"); dir != "" {
+		return dir, os.MkdirAll(dir, 0o755)
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	dir := xdgUserDir(home, "DOWNLOAD")
+	if dir == "" {
+		dir = filepath.Join(home, "Downloads")
+	}
+	return dir, os.MkdirAll(dir, 0o755)
+}
+
+// xdgUserDir 从 $XDG_CONFIG_HOME/user-dirs.dirs 读取用户目录，例如 XDG_DOWNLOAD_DIR="$HOME/Downloads"；
+// 桌面环境通常只在该文件中设置而不导出环境变量
+func xdgUserDir(home, name string) string {
+	config := os.Getenv("XDG_CONFIG_HOME")
+	if config == "" {
+		config = filepath.Join(home, ".config")
+	}
+	data, err := os.ReadFile(filepath.Join(config, "user-dirs.dirs"))
+	if err != nil {
+		return ""
+	}
+	prefix := "XDG_" + name + "_DIR="
+	for _, line := range strings.Split(string(data), "\n") {
+		value, ok := strings.CutPrefix(strings.TrimSpace(line), prefix)
+		if !ok {
+			continue
+		}
+		value = strings.Trim(value, `"`)
+		if rest, ok := strings.CutPrefix(value, "$HOME"); ok {
+			value = home + rest
+		}
+		if filepath.IsAbs(value) {
+			return filepath.Clean(value)
+		}
+	}
+	return ""
+}
+
+// sanitizeDownloadName 去掉服务器建议文件名中的路径与非法字符
+func sanitizeDownloadName(name string) string {
+	name = strings.ReplaceAll(name, `\`, "/")
+	name = name[strings.LastIndex(name, "/")+1:]
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || strings.ContainsRune(`<>:"|?*`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	name = strings.Trim(name, " .")
+	if name == "" {
+		return "download"
+	}
+	return name
+}
+
+// uniqueDownloadPath 文件已存在或已被进行中的下载占用时在扩展名前加 " (n)"。
+// 原生库要到下载开始写入后才创建文件，因此同时开始的同名下载需要通过 downloadRegistry 区分
+func uniqueDownloadPath(path string) string {
+	if !downloadPathTaken(path) {
+		return path
+	}
+	ext := filepath.Ext(path)
+	base := strings.TrimSuffix(path, ext)
+	for n := 1; ; n++ {
+		candidate := base + " (" + strconv.Itoa(n) + ")" + ext
+		if !downloadPathTaken(candidate) {
+			return candidate
+		}
+	}
+}
+
+func downloadPathTaken(path string) bool {
+	if _, err := os.Lstat(path); !os.IsNotExist(err) {
+		return true
+	}
+	downloadMutex.Lock()
+	defer downloadMutex.Unlock()
+	for _, d := range downloadRegistry {
+		if samePath(d.Path(), path) {
+			return true
+		}
+	}
+	return false
+}
+
+// samePath Windows 与 macOS 的文件系统默认不区分大小写
+func samePath(a, b string) bool {
+	a, b = filepath.Clean(a), filepath.Clean(b)
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+// installDownloads 安装下载回调，并更新下载状态、转发给页面
+func (w *Webview) installDownloads() {
+	if webviewSetDownloadCallback == nil || webviewDownloadSetDestination == nil {
+		return
+	}
+	downloadCallbackOnce.Do(func() { downloadCallback = purego.NewCallback(cDownloadHandler) })
+	mainScheduler.RunInMainThread(func() { webviewSetDownloadCallback(w, downloadCallback) })
+
+	// 创建窗口时最先订阅，用户的处理函数运行时 Download 的状态已经更新
+	w.On(EventDownloadProgress, func(wv *Webview, ev Event) {
+		progress := ev.(DownloadProgressEvent)
+		progress.Download.applyProgress(progress)
+		_ = wv.Emit("downloadprogress", progress.Download.pageInfo(wv))
+	})
+	w.On(EventDownloadFinished, func(wv *Webview, ev Event) {
+		finished := ev.(DownloadFinishedEvent)
+		finished.Download.applyFinished(finished)
+		downloadMutex.Lock()
+		delete(downloadRegistry, finished.Download.ID)
+		downloadMutex.Unlock()
+		_ = wv.Emit("downloadfinished", finished.Download.pageInfo(wv))
+	})
+}
+
+func (d *Download) applyProgress(ev DownloadProgressEvent) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.received, d.total = ev.ReceivedBytes, ev.TotalBytes
+}
+
+func (d *Download) applyFinished(ev DownloadFinishedEvent) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.state, d.errMsg = ev.State, ev.Error
+	if ev.Path != "" {
+		d.path = ev.Path
+	}
+	if ev.State == DownloadCompleted && d.total < 0 {
+		d.total = d.received
+	}
+}
+
+// releaseDownloads 窗口关闭时清理下载记录
+func releaseDownloads(wv *Webview) {
+	downloadMutex.Lock()
+	defer downloadMutex.Unlock()
+	delete(downloadHandlers, wv)
+	for id, d := range downloadRegistry {
+		if d.window == wv {
+			delete(downloadRegistry, id)
+		}
+	}
+}
+
+// cancelDownloadFromJS 只能取消调用窗口自己的下载
+func cancelDownloadFromJS(wv *Webview, args []any) error {
+	if len(args) < 1 {
+		return fmt.Errorf("missing download id")
+	}
+	id, ok := args[0].(float64)
+	if !ok {
+		return fmt.Errorf("invalid download id")
+	}
+	d := lookupDownload(uint64(id))
+	if d == nil || d.window != wv {
+		return fmt.Errorf("unknown download %v", args[0])
+	}
+	return d.Cancel()
+}
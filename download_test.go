@@ -0,0 +1,202 @@
+package wvapp
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestSanitizeDownloadName(t *testing.T) {
+	for in, want := range map[string]string{
+		"report.pdf":          "report.pdf",
+		"../../etc/passwd":    "passwd",
+		`..\..\boot.ini`:      "boot.ini",
+		"a:b?.txt":            "a_b_.txt",
+		"..":                  "download",
+		"":                    "download",
+		" notes.txt. ":        "notes.txt",
+		"line\nbreak.txt":     "line_break.txt",
+		"quarterly report.cs": "quarterly report.cs",
+	} {
+		if got := sanitizeDownloadName(in); got != want {
+			t.Errorf("sanitizeDownloadName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestDownloadDestination(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "report.pdf"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "report (1).pdf"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := downloadDestination(dir, "report.pdf")
+	if err != nil || got != filepath.Join(dir, "report (2).pdf") {
+		t.Fatalf("directory destination = %q, %v", got, err)
+	}
+
+	explicit := filepath.Join(dir, "sub", "report.pdf")
+	got, err = downloadDestination(explicit, "ignored.pdf")
+	if err != nil || got != explicit {
+		t.Fatalf("explicit destination = %q, %v", got, err)
+	}
+	if _, err := os.Stat(filepath.Dir(explicit)); err != nil {
+		t.Fatalf("parent directory not created: %v", err)
+	}
+
+	if _, err := downloadDestination("relative/file.pdf", "file.pdf"); err == nil {
+		t.Fatal("expected an error for a relative path")
+	}
+
+	t.Setenv("XDG_DOWNLOAD_DIR", filepath.Join(dir, "downloads"))
+	got, err = downloadDestination("", "data.csv")
+	if err != nil || got != filepath.Join(dir, "downloads", "data.csv") {
+		t.Fatalf("default destination = %q, %v", got, err)
+	}
+}
+
+func TestDownloadDestinationReservesPaths(t *testing.T) {
+	wv := fakeWebview()
+	defer releaseDownloads(wv)
+	dir := t.TempDir()
+
+	// 第一个下载已经开始但原生库尚未创建文件
+	first, err := downloadDestination(dir, "report.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	downloadMutex.Lock()
+	downloadRegistry[1] = &Download{ID: 1, window: wv, path: first}
+	downloadMutex.Unlock()
+
+	second, err := downloadDestination(dir, "report.pdf")
+	if err != nil || second != filepath.Join(dir, "report (1).pdf") {
+		t.Fatalf("second destination = %q, %v; want a different file than %q", second, err, first)
+	}
+}
+
+func TestUserDownloadsDirFromUserDirs(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("user-dirs.dirs is an XDG convention")
+	}
+	home := t.TempDir()
+	config := filepath.Join(home, ".config")
+	if err := os.MkdirAll(config, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	dirs := "# written by xdg-user-dirs-update\nXDG_DESKTOP_DIR=\"$HOME/Desktop\"\nXDG_DOWNLOAD_DIR=\"$HOME/Téléchargements\"\n"
+	if err := os.WriteFile(filepath.Join(config, "user-dirs.dirs"), []byte(dirs), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_DOWNLOAD_DIR", "")
+
+	got, err := userDownloadsDir()
+	if want := filepath.Join(home, "Téléchargements"); err != nil || got != want {
+		t.Fatalf("userDownloadsDir() = %q, %v; want %q", got, err, want)
+	}
+	if xdgUserDir(home, "MUSIC") != "" {
+		t.Fatal("missing entry should be empty")
+	}
+}
+
+func TestDecideDownload(t *testing.T) {
+	wv := fakeWebview()
+	defer releaseDownloads(wv)
+	dir := t.TempDir()
+	d := &Download{ID: 1, URL: "https://example.com/a.zip", SuggestedName: "a.zip", window: wv}
+
+	wv.OnDownloadStarted(func(d *Download) DownloadDecision { return DownloadDecision{Path: dir} })
+	if path, ok := wv.decideDownload(d); !ok || path != filepath.Join(dir, "a.zip") {
+		t.Fatalf("decideDownload = %q, %v", path, ok)
+	}
+
+	wv.OnDownloadStarted(func(d *Download) DownloadDecision { return DownloadDecision{Cancel: true} })
+	if _, ok := wv.decideDownload(d); ok {
+		t.Fatal("canceled download was accepted")
+	}
+
+	wv.OnDownloadStarted(func(d *Download) DownloadDecision { panic("boom") })
+	if _, ok := wv.decideDownload(d); ok {
+		t.Fatal("download accepted after the handler panicked")
+	}
+}
+
+func TestDownloadEvents(t *testing.T) {
+	wv := fakeWebview()
+	d := &Download{ID: 42, URL: "https://example.com/big.iso", window: wv, total: -1}
+	downloadMutex.Lock()
+	downloadRegistry[d.ID] = d
+	downloadMutex.Unlock()
+	defer releaseDownloads(wv)
+
+	ev, err := decodeEvent(EventDownloadProgress, []byte(`{"id":42,"received":512,"total":-1}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	progress := ev.(DownloadProgressEvent)
+	if progress.Download != d || progress.ReceivedBytes != 512 || progress.TotalBytes != -1 {
+		t.Fatalf("progress = %+v", progress)
+	}
+	d.applyProgress(progress)
+
+	ev, err = decodeEvent(EventDownloadFinished, []byte(`{"id":42,"state":"completed","path":"/tmp/big.iso"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.applyFinished(ev.(DownloadFinishedEvent))
+	if d.State() != DownloadCompleted || d.Path() != "/tmp/big.iso" {
+		t.Fatalf("state = %v, path = %q", d.State(), d.Path())
+	}
+	if received, total := d.Progress(); received != 512 || total != 512 {
+		t.Fatalf("progress = %d/%d", received, total)
+	}
+	if err := d.Cancel(); err != nil {
+		t.Fatalf("Cancel on a finished download: %v", err)
+	}
+
+	if _, err := decodeEvent(EventDownloadProgress, []byte(`{"id":7}`)); err == nil {
+		t.Fatal("expected an error for an unknown download")
+	}
+	if parseDownloadState("exploded") != DownloadFailed {
+		t.Fatal("unknown states should be treated as failures")
+	}
+}
+
+func TestDownloadPageInfoHidesPathFromRemotePages(t *testing.T) {
+	registeredScheme.Store("wvapp")
+	defer registeredScheme.Store("")
+	wv := fakeWebview()
+	defer releaseWindow(wv)
+	wv.trackBridgeOrigin()
+	d := &Download{ID: 43, URL: "https://example.com/a.zip", SuggestedName: "a.zip", window: wv, path: "/home/u/Downloads/a.zip"}
+
+	wv.dispatchEvent(NavigationFinishedEvent{URL: "https://example.com/"})
+	if info := d.pageInfo(wv); info.Path != "" || info.SuggestedName != "a.zip" {
+		t.Fatalf("remote page received %+v", info)
+	}
+	wv.dispatchEvent(NavigationFinishedEvent{URL: "wvapp://app/index.html"})
+	if info := d.pageInfo(wv); info.Path != "/home/u/Downloads/a.zip" {
+		t.Fatalf("app page should receive the path, got %+v", info)
+	}
+}
+
+func TestCancelDownloadFromJSChecksWindow(t *testing.T) {
+	owner, other := fakeWebview(), fakeWebview()
+	downloadMutex.Lock()
+	downloadRegistry[99] = &Download{ID: 99, window: owner}
+	downloadMutex.Unlock()
+	defer releaseDownloads(owner)
+
+	if err := cancelDownloadFromJS(other, []any{float64(99)}); err == nil {
+		t.Fatal("another window canceled the download")
+	}
+	if err := cancelDownloadFromJS(owner, []any{"99"}); err == nil {
+		t.Fatal("expected an error for a non-numeric id")
+	}
+}
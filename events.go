@@ -0,0 +1,271 @@
+package wvapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/ebitengine/purego"
+)
+
+// Event 窗口事件，使用类型断言获取具体事件携带的数据
+type Event interface {
+	EventType() EventType
+}
+
+// EventHandler 事件处理函数，在主线程中调用，不应执行耗时操作
+type EventHandler func(wv *Webview, ev Event)
+
+type CloseEvent struct{}
+
+type DomReadyEvent struct{}
+
+type NavigationStartedEvent struct {
+	URL string
+}
+
+type NavigationFinishedEvent struct {
+	URL string
+}
+
+type LoadFailedEvent struct {
+	URL       string
+	ErrorCode int    // 平台相关的错误码
+	Message   string // 错误描述
+}
+
+type TitleChangedEvent struct {
+	Title string
+}
+
+type FocusEvent struct{}
+
+type BlurEvent struct{}
+
+type ResizeEvent struct {
+	Width  int
+	Height int
+}
+
+type MoveEvent struct {
+	X int
+	Y int
+}
+
+type MinimizeEvent struct{}
+
+type MaximizeEvent struct{}
+
+type RestoreEvent struct{}
+
+type FullscreenChangedEvent struct {
+	Fullscreen bool
+}
+
+// FileDropEvent 文件拖放事件，X/Y 为相对窗口内容区左上角的坐标
+type FileDropEvent struct {
+	Paths []string
+	X     int
+	Y     int
+}
+
+// DownloadProgressEvent 下载进度，TotalBytes 为 -1 表示总大小未知
+type DownloadProgressEvent struct {
+	Download      *Download
+	ReceivedBytes int64
+	TotalBytes    int64
+}
+
+// DownloadFinishedEvent 下载结束，State 为 DownloadCompleted、DownloadCanceled 或 DownloadFailed
+type DownloadFinishedEvent struct {
+	Download *Download
+	State    DownloadState
+	Path     string // 实际保存路径
+	Error    string // 失败原因
+}
+
+func (CloseEvent) EventType() EventType              { return EventClose }
+func (DomReadyEvent) EventType() EventType           { return EventDomReady }
+func (NavigationStartedEvent) EventType() EventType  { return EventNavigationStarted }
+func (NavigationFinishedEvent) EventType() EventType { return EventNavigationFinished }
+func (LoadFailedEvent) EventType() EventType         { return EventLoadFailed }
+func (TitleChangedEvent) EventType() EventType       { return EventTitleChanged }
+func (FocusEvent) EventType() EventType              { return EventFocus }
+func (BlurEvent) EventType() EventType               { return EventBlur }
+func (ResizeEvent) EventType() EventType             { return EventResize }
+func (MoveEvent) EventType() EventType               { return EventMove }
+func (MinimizeEvent) EventType() EventType           { return EventMinimize }
+func (MaximizeEvent) EventType() EventType           { return EventMaximize }
+func (RestoreEvent) EventType() EventType            { return EventRestore }
+func (FullscreenChangedEvent) EventType() EventType  { return EventFullscreenChanged }
+func (FileDropEvent) EventType() EventType           { return EventFileDrop }
+func (DownloadProgressEvent) EventType() EventType   { return EventDownloadProgress }
+func (DownloadFinishedEvent) EventType() EventType   { return EventDownloadFinished }
+
+// eventData 原生库通过 JSON 传递的事件数据
+type eventData struct {
+	URL        string   `json:"url"`
+	Code       int      `json:"code"`
+	Message    string   `json:"message"`
+	Title      string   `json:"title"`
+	Width      int      `json:"width"`
+	Height     int      `json:"height"`
+	X          int      `json:"x"`
+	Y          int      `json:"y"`
+	Fullscreen bool     `json:"fullscreen"`
+	Paths      []string `json:"paths"`
+	ID         uint64   `json:"id"`
+	Received   int64    `json:"received"`
+	Total      int64    `json:"total"`
+	State      string   `json:"state"`
+	Path       string   `json:"path"`
+}
+
+// decodeEvent 根据事件类型与 JSON 数据构造具体的事件结构体
+func decodeEvent(eventType EventType, data []byte) (Event, error) {
+	var d eventData
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &d); err != nil {
+			return nil, fmt.Errorf("invalid data for event %d: %w", eventType, err)
+		}
+	}
+	switch eventType {
+	case EventClose:
+		return CloseEvent{}, nil
+	case EventDomReady:
+		return DomReadyEvent{}, nil
+	case EventNavigationStarted:
+		return NavigationStartedEvent{URL: d.URL}, nil
+	case EventNavigationFinished:
+		return NavigationFinishedEvent{URL: d.URL}, nil
+	case EventLoadFailed:
+		return LoadFailedEvent{URL: d.URL, ErrorCode: d.Code, Message: d.Message}, nil
+	case EventTitleChanged:
+		return TitleChangedEvent{Title: d.Title}, nil
+	case EventFocus:
+		return FocusEvent{}, nil
+	case EventBlur:
+		return BlurEvent{}, nil
+	case EventResize:
+		return ResizeEvent{Width: d.Width, Height: d.Height}, nil
+	case EventMove:
+		return MoveEvent{X: d.X, Y: d.Y}, nil
+	case EventMinimize:
+		return MinimizeEvent{}, nil
+	case EventMaximize:
+		return MaximizeEvent{}, nil
+	case EventRestore:
+		return RestoreEvent{}, nil
+	case EventFullscreenChanged:
+		return FullscreenChangedEvent{Fullscreen: d.Fullscreen}, nil
+	case EventFileDrop:
+		return FileDropEvent{Paths: d.Paths, X: d.X, Y: d.Y}, nil
+	case EventDownloadProgress, EventDownloadFinished:
+		download := lookupDownload(d.ID)
+		if download == nil {
+			return nil, fmt.Errorf("unknown download %d", d.ID)
+		}
+		if eventType == EventDownloadProgress {
+			return DownloadProgressEvent{Download: download, ReceivedBytes: d.Received, TotalBytes: d.Total}, nil
+		}
+		return DownloadFinishedEvent{Download: download, State: parseDownloadState(d.State), Path: d.Path, Error: d.Message}, nil
+	}
+	return nil, fmt.Errorf("unknown event type %d", eventType)
+}
+
+type eventSubscription struct {
+	id      uint64
+	handler EventHandler
+}
+
+var (
+	eventRegistry         = make(map[*Webview]map[EventType][]eventSubscription)
+	eventMutex            sync.Mutex
+	eventNextID           uint64
+	eventDataCallback     uintptr
+	eventDataCallbackOnce sync.Once
+)
+
+// On 订阅窗口事件，同一事件可注册多个处理函数，返回取消订阅函数
+func (w *Webview) On(eventType EventType, handler EventHandler) (unsubscribe func()) {
+	if w == nil || handler == nil {
+		return func() {}
+	}
+	eventMutex.Lock()
+	eventNextID++
+	id := eventNextID
+	if _, ok := eventRegistry[w]; !ok {
+		eventRegistry[w] = make(map[EventType][]eventSubscription)
+	}
+	eventRegistry[w][eventType] = append(eventRegistry[w][eventType], eventSubscription{id: id, handler: handler})
+	eventMutex.Unlock()
+
+	return func() {
+		eventMutex.Lock()
+		defer eventMutex.Unlock()
+		subs := eventRegistry[w][eventType]
+		for i, sub := range subs {
+			if sub.id == id {
+				eventRegistry[w][eventType] = append(subs[:i:i], subs[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// dispatchEvent 将事件分发给所有订阅者，单个处理函数 panic 不影响其他订阅者
+func (w *Webview) dispatchEvent(ev Event) {
+	eventMutex.Lock()
+	subs := append([]eventSubscription(nil), eventRegistry[w][ev.EventType()]...)
+	eventMutex.Unlock()
+
+	for _, sub := range subs {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					slog.Error("Panic in event handler", "event", ev.EventType(), "panic", r)
+				}
+			}()
+			sub.handler(w, ev)
+		}()
+	}
+}
+
+// Emit 向页面发送自定义事件，JS 中通过 window.runtime.On(name, handler) 接收，
+// 同时以 "wvapp:"+name 为类型派发 DOM CustomEvent
+func (w *Webview) Emit(name string, data any) error {
+	if w == nil {
+		return fmt.Errorf("webview: nil webview")
+	}
+	nameJSON, err := json.Marshal(name)
+	if err != nil {
+		return err
+	}
+	dataJSON, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("webview: cannot encode event %q: %w", name, err)
+	}
+	w.EvalJS(fmt.Sprintf("window._wvappEmit && window._wvappEmit(%s, %s);", nameJSON, dataJSON))
+	return nil
+}
+
+// cEventDataHandler 扩展事件回调，数据以 JSON 字符串传递
+func cEventDataHandler(wv *Webview, eventType int32, dataPtr uintptr) uintptr {
+	ev, err := decodeEvent(EventType(eventType), []byte(goString(dataPtr)))
+	if err != nil {
+		slog.Warn("Dropping native event", "error", err)
+		return 0
+	}
+	wv.dispatchEvent(ev)
+	return 0
+}
+
+// installEventDataCallback 安装扩展事件回调，原生库不支持时仅能收到 Close/DomReady
+func (w *Webview) installEventDataCallback() {
+	if webviewSetEventDataCallback == nil {
+		return
+	}
+	eventDataCallbackOnce.Do(func() { eventDataCallback = purego.NewCallback(cEventDataHandler) })
+	mainScheduler.RunInMainThread(func() { webviewSetEventDataCallback(w, eventDataCallback) })
+}
@@ -0,0 +1,75 @@
+package wvapp
+
+import "testing"
+
+func TestDecodeEvent(t *testing.T) {
+	cases := []struct {
+		eventType EventType
+		data      string
+		want      Event
+	}{
+		{EventClose, "", CloseEvent{}},
+		{EventDomReady, "", DomReadyEvent{}},
+		{EventNavigationStarted, `{"url":"https://example.com/"}`, NavigationStartedEvent{URL: "https://example.com/"}},
+		{EventNavigationFinished, `{"url":"wvapp://index.html/"}`, NavigationFinishedEvent{URL: "wvapp://index.html/"}},
+		{EventLoadFailed, `{"url":"https://x/","code":-1009,"message":"offline"}`, LoadFailedEvent{URL: "https://x/", ErrorCode: -1009, Message: "offline"}},
+		{EventTitleChanged, `{"title":"Hello"}`, TitleChangedEvent{Title: "Hello"}},
+		{EventFocus, "", FocusEvent{}},
+		{EventBlur, "{}", BlurEvent{}},
+		{EventResize, `{"width":1024,"height":768}`, ResizeEvent{Width: 1024, Height: 768}},
+		{EventMove, `{"x":-20,"y":40}`, MoveEvent{X: -20, Y: 40}},
+		{EventMinimize, "", MinimizeEvent{}},
+		{EventMaximize, "", MaximizeEvent{}},
+		{EventRestore, "", RestoreEvent{}},
+		{EventFullscreenChanged, `{"fullscreen":true}`, FullscreenChangedEvent{Fullscreen: true}},
+	}
+	for _, c := range cases {
+		got, err := decodeEvent(c.eventType, []byte(c.data))
+		if err != nil {
+			t.Errorf("decodeEvent(%d): %v", c.eventType, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("decodeEvent(%d) = %#v, want %#v", c.eventType, got, c.want)
+		}
+		if got.EventType() != c.eventType {
+			t.Errorf("%T.EventType() = %d, want %d", got, got.EventType(), c.eventType)
+		}
+	}
+
+	if _, err := decodeEvent(EventType(999), nil); err == nil {
+		t.Error("expected error for unknown event type")
+	}
+	if _, err := decodeEvent(EventResize, []byte("{")); err == nil {
+		t.Error("expected error for malformed data")
+	}
+}
+
+func TestOnMultipleSubscribers(t *testing.T) {
+	wv := fakeWebview()
+	defer releaseWindow(wv)
+
+	var first, second []Event
+	unsubscribe := wv.On(EventResize, func(_ *Webview, ev Event) { first = append(first, ev) })
+	wv.On(EventResize, func(_ *Webview, ev Event) { second = append(second, ev) })
+	wv.On(EventResize, func(*Webview, Event) { panic("boom") })
+	wv.On(EventMove, func(*Webview, Event) { t.Error("move handler should not receive resize events") })
+
+	wv.dispatchEvent(ResizeEvent{Width: 10, Height: 20})
+	unsubscribe()
+	wv.dispatchEvent(ResizeEvent{Width: 30, Height: 40})
+
+	if len(first) != 1 || first[0] != (ResizeEvent{Width: 10, Height: 20}) {
+		t.Fatalf("first subscriber got %v", first)
+	}
+	if len(second) != 2 {
+		t.Fatalf("second subscriber got %v", second)
+	}
+
+	other := fakeWebview()
+	defer releaseWindow(other)
+	other.dispatchEvent(ResizeEvent{})
+	if len(second) != 2 {
+		t.Fatal("events must not leak across windows")
+	}
+}
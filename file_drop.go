@@ -0,0 +1,83 @@
+package wvapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+)
+
+// FileDropOptions 文件拖放选项
+type FileDropOptions struct {
+	// PreventDefault 为 true 时只触发 OnFileDrop 与 JS 的 filedrop 事件，
+	// 网页不会收到原生 drop 事件，也不会导航到被拖入的文件
+	PreventDefault bool
+}
+
+// fileDropDetail 发送给页面的 filedrop 事件数据
+type fileDropDetail struct {
+	Paths []string `json:"paths"`
+	X     int      `json:"x"`
+	Y     int      `json:"y"`
+}
+
+// OnFileDrop 订阅文件拖放，paths 为文件在磁盘上的真实路径，返回取消订阅函数
+func (w *Webview) OnFileDrop(fn func(paths []string, x, y int)) (unsubscribe func()) {
+	if fn == nil {
+		return func() {}
+	}
+	return w.On(EventFileDrop, func(_ *Webview, ev Event) {
+		drop := ev.(FileDropEvent)
+		fn(drop.Paths, drop.X, drop.Y)
+	})
+}
+
+// SetFileDropOptions 修改文件拖放行为
+func (w *Webview) SetFileDropOptions(opts FileDropOptions) error {
+	if webviewSetFileDropOptions == nil {
+		return ErrNotSupported
+	}
+	mainScheduler.RunInMainThread(func() { webviewSetFileDropOptions(w, opts.PreventDefault) })
+	return nil
+}
+
+// installFileDrop 应用创建选项，并把拖放事件转发给页面
+func (w *Webview) installFileDrop(options *WindowOptions) {
+	if options.FileDrop.PreventDefault {
+		if err := w.SetFileDropOptions(options.FileDrop); err != nil {
+			slog.Warn("File drop options ignored", "error", err)
+		}
+	}
+	w.On(EventFileDrop, func(wv *Webview, ev Event) {
+		drop := ev.(FileDropEvent)
+		if !wv.trustedPage() {
+			drop = withFileNamesOnly(drop)
+		}
+		if script, err := fileDropScript(drop); err == nil {
+			wv.EvalJS(script)
+		}
+	})
+}
+
+// withFileNamesOnly 去掉路径中的目录，不把本地文件系统结构暴露给远程来源的页面
+func withFileNamesOnly(ev FileDropEvent) FileDropEvent {
+	names := make([]string, len(ev.Paths))
+	for i, path := range ev.Paths {
+		names[i] = filepath.Base(path)
+	}
+	ev.Paths = names
+	return ev
+}
+
+// fileDropScript 由 runtime.js 根据坐标找到目标元素后派发事件
+func fileDropScript(ev FileDropEvent) (string, error) {
+	paths := ev.Paths
+	if paths == nil {
+		paths = []string{}
+	}
+	data, err := json.Marshal(fileDropDetail{Paths: paths, X: ev.X, Y: ev.Y})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("window._wvappFileDrop && window._wvappFileDrop(%s);", data), nil
+}
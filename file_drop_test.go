@@ -0,0 +1,90 @@
+package wvapp
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFileDropEvent(t *testing.T) {
+	ev, err := decodeEvent(EventFileDrop, []byte(`{"paths":["/home/u/a.txt","/home/u/b c.png"],"x":120,"y":48}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := FileDropEvent{Paths: []string{"/home/u/a.txt", "/home/u/b c.png"}, X: 120, Y: 48}
+	if !reflect.DeepEqual(ev, want) {
+		t.Fatalf("decodeEvent = %+v, want %+v", ev, want)
+	}
+
+	wv := fakeWebview()
+	defer releaseWindow(wv)
+	var got []string
+	var gotX, gotY int
+	unsubscribe := wv.OnFileDrop(func(paths []string, x, y int) {
+		got, gotX, gotY = paths, x, y
+	})
+	wv.dispatchEvent(ev)
+	if !reflect.DeepEqual(got, want.Paths) || gotX != 120 || gotY != 48 {
+		t.Fatalf("OnFileDrop got %v at %d,%d", got, gotX, gotY)
+	}
+
+	unsubscribe()
+	got = nil
+	wv.dispatchEvent(ev)
+	if got != nil {
+		t.Fatal("handler called after unsubscribe")
+	}
+}
+
+func TestFileDropScript(t *testing.T) {
+	script, err := fileDropScript(FileDropEvent{Paths: []string{`C:\Users\u\"quoted".txt`}, X: 1, Y: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `window._wvappFileDrop({"paths":["C:\\Users\\u\\\"quoted\".txt"],"x":1,"y":2});`
+	if !strings.HasSuffix(script, want) {
+		t.Fatalf("script = %s", script)
+	}
+	if script, _ := fileDropScript(FileDropEvent{}); !strings.Contains(script, `"paths":[]`) {
+		t.Fatalf("empty drop should send an empty array: %s", script)
+	}
+}
+
+func TestFileDropNamesOnlyForUntrustedPages(t *testing.T) {
+	registeredScheme.Store("wvapp")
+	defer registeredScheme.Store("")
+	wv := fakeWebview()
+	defer releaseWindow(wv)
+	wv.trackBridgeOrigin()
+
+	drop := FileDropEvent{Paths: []string{filepath.Join("home", "u", "secret", "a.txt"), "b.png"}, X: 1, Y: 2}
+	if got := withFileNamesOnly(drop); !reflect.DeepEqual(got.Paths, []string{"a.txt", "b.png"}) || got.X != 1 {
+		t.Fatalf("withFileNamesOnly = %+v", got)
+	}
+	if drop.Paths[0] == "a.txt" {
+		t.Fatal("the original event must not be modified")
+	}
+
+	// 页面 URL 未知时不可信
+	if wv.trustedPage() {
+		t.Fatal("a page with no recorded URL must not be trusted")
+	}
+	wv.dispatchEvent(NavigationFinishedEvent{URL: "https://example.com/"})
+	if wv.trustedPage() {
+		t.Fatal("remote pages must not receive local paths")
+	}
+	wv.dispatchEvent(NavigationFinishedEvent{URL: "wvapp://app/index.html"})
+	if !wv.trustedPage() {
+		t.Fatal("app pages should receive local paths")
+	}
+	// 设置策略后以策略为准
+	wv.SetBridgeOriginPolicy(&OriginPolicy{AllowedOrigins: []string{"https://example.com"}})
+	if wv.trustedPage() {
+		t.Fatal("origins outside the policy must not receive local paths")
+	}
+	wv.dispatchEvent(NavigationFinishedEvent{URL: "https://example.com/app"})
+	if !wv.trustedPage() {
+		t.Fatal("origins allowed by the policy should receive local paths")
+	}
+}
@@ -4,5 +4,6 @@ go 1.24.3
 
 require (
 	github.com/ebitengine/purego v0.8.3
+	github.com/godbus/dbus/v5 v5.1.0
 	github.com/millken/goid v1.0.0
 )
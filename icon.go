@@ -0,0 +1,28 @@
+package wvapp
+
+import (
+	"bytes"
+	"fmt"
+	"unsafe"
+)
+
+var (
+	pngSignature = []byte("\x89PNG\r\n\x1a\n")
+	icoSignature = []byte{0, 0, 1, 0}
+)
+
+// validateIcon 检查图标是否为 PNG 或 ICO 格式，空图标视为有效（使用默认图标）
+func validateIcon(icon []byte) error {
+	if len(icon) == 0 || bytes.HasPrefix(icon, pngSignature) || bytes.HasPrefix(icon, icoSignature) {
+		return nil
+	}
+	return fmt.Errorf("webview: icon must be PNG or ICO data")
+}
+
+// iconPointer 返回传给原生库的图标指针与长度，调用方需在原生调用结束前保持 icon 存活
+func iconPointer(icon []byte) (uintptr, uint64) {
+	if len(icon) == 0 {
+		return 0, 0
+	}
+	return uintptr(unsafe.Pointer(&icon[0])), uint64(len(icon))
+}
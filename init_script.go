@@ -0,0 +1,121 @@
+package wvapp
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"unsafe"
+)
+
+// InitScriptID 初始化脚本标识，用于 RemoveInitScript
+type InitScriptID uint64
+
+type initScript struct {
+	id     InitScriptID
+	source string
+}
+
+var (
+	initScriptRegistry = make(map[*Webview][]initScript)
+	initScriptMutex    sync.Mutex
+	initScriptNextID   InitScriptID
+)
+
+// initScriptsSupported 原生库是否支持在页面脚本之前执行的初始化脚本
+func initScriptsSupported() bool {
+	return webviewAddInitScript != nil && webviewClearInitScripts != nil
+}
+
+// AddInitScript 添加初始化脚本，每次导航时在页面脚本之前执行（仅主 frame）
+//
+// 原生库不支持时退化为 DomReady 后通过 EvalJS 执行，此时页面脚本先于初始化脚本运行。
+func (w *Webview) AddInitScript(js string) (InitScriptID, error) {
+	if w == nil {
+		return 0, fmt.Errorf("webview: nil webview")
+	}
+	initScriptMutex.Lock()
+	initScriptNextID++
+	id := initScriptNextID
+	initScriptRegistry[w] = append(initScriptRegistry[w], initScript{id: id, source: js})
+	initScriptMutex.Unlock()
+
+	if initScriptsSupported() {
+		mainScheduler.RunInMainThread(func() {
+			cstr, ptr := cString(js)
+			webviewAddInitScript(w, ptr)
+			runtime.KeepAlive(cstr)
+		})
+	}
+	return id, nil
+}
+
+// RemoveInitScript 移除初始化脚本，从下一次导航开始生效
+func (w *Webview) RemoveInitScript(id InitScriptID) error {
+	initScriptMutex.Lock()
+	scripts := initScriptRegistry[w]
+	index := -1
+	for i, s := range scripts {
+		if s.id == id {
+			index = i
+			break
+		}
+	}
+	if index < 0 {
+		initScriptMutex.Unlock()
+		return fmt.Errorf("webview: init script %d not found", id)
+	}
+	scripts = append(scripts[:index:index], scripts[index+1:]...)
+	initScriptRegistry[w] = scripts
+	remaining := make([]string, len(scripts))
+	for i, s := range scripts {
+		remaining[i] = s.source
+	}
+	initScriptMutex.Unlock()
+
+	if initScriptsSupported() {
+		// 原生端不支持单独移除，清空后按原顺序重建
+		mainScheduler.RunInMainThread(func() {
+			webviewClearInitScripts(w)
+			for _, js := range remaining {
+				cstr, ptr := cString(js)
+				webviewAddInitScript(w, ptr)
+				runtime.KeepAlive(cstr)
+			}
+		})
+	}
+	return nil
+}
+
+// installRuntimeScript 通过初始化脚本注册 runtime.js，并在每次导航开始时重新绑定函数。
+// 原生库不支持扩展事件时收不到 NavigationStarted，改在 DomReady 时重新绑定。
+func (w *Webview) installRuntimeScript() {
+	if !initScriptsSupported() {
+		return
+	}
+	if len(runtimeJS) > 0 {
+		_, _ = w.AddInitScript(unsafe.String(&runtimeJS[0], len(runtimeJS)))
+	}
+	rebindOn := EventNavigationStarted
+	if webviewSetEventDataCallback == nil {
+		rebindOn = EventDomReady
+	}
+	w.On(rebindOn, func(wv *Webview, _ Event) { wv.rebindAll() })
+}
+
+// onDomReadyFallback 原生库不支持初始化脚本时，在 DomReady 中补注入 runtime.js、
+// 用户初始化脚本并重新绑定函数。必须在主线程中调用。
+func (w *Webview) onDomReadyFallback() {
+	if initScriptsSupported() {
+		return
+	}
+	if len(runtimeJS) > 0 {
+		w.EvalJS(unsafe.String(&runtimeJS[0], len(runtimeJS)))
+	}
+	initScriptMutex.Lock()
+	scripts := append([]initScript(nil), initScriptRegistry[w]...)
+	initScriptMutex.Unlock()
+	for _, s := range scripts {
+		w.EvalJS(s.source)
+	}
+	w.rebindAll()
+}
@@ -0,0 +1,79 @@
+package wvapp
+
+import (
+	"testing"
+	"unsafe"
+)
+
+func TestInitScriptBookkeeping(t *testing.T) {
+	wv := fakeWebview()
+	defer releaseWindow(wv)
+
+	first, err := wv.AddInitScript("window.a = 1;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _ := wv.AddInitScript("window.b = 2;")
+	third, _ := wv.AddInitScript("window.c = 3;")
+	if first == second || second == third {
+		t.Fatal("init script IDs must be unique")
+	}
+
+	if err := wv.RemoveInitScript(second); err != nil {
+		t.Fatal(err)
+	}
+	if err := wv.RemoveInitScript(second); err == nil {
+		t.Fatal("removing an unknown init script should fail")
+	}
+
+	initScriptMutex.Lock()
+	scripts := initScriptRegistry[wv]
+	initScriptMutex.Unlock()
+	if len(scripts) != 2 || scripts[0].id != first || scripts[1].id != third {
+		t.Fatalf("unexpected remaining scripts: %+v", scripts)
+	}
+
+	releaseWindow(wv)
+	if err := wv.RemoveInitScript(first); err == nil {
+		t.Fatal("init scripts should be released with the window")
+	}
+}
+
+func TestRuntimeScriptRebindsOncePerNavigation(t *testing.T) {
+	saved := []any{webviewAddInitScript, webviewClearInitScripts, webviewBind, webviewSetEventDataCallback}
+	defer func() {
+		mainScheduler.PollTasks()
+		webviewAddInitScript = saved[0].(func(*Webview, uintptr))
+		webviewClearInitScripts = saved[1].(func(*Webview))
+		webviewBind = saved[2].(func(*Webview, uintptr, uintptr, unsafe.Pointer))
+		webviewSetEventDataCallback = saved[3].(func(*Webview, uintptr))
+	}()
+	webviewAddInitScript = func(*Webview, uintptr) {}
+	webviewClearInitScripts = func(*Webview) {}
+	binds := 0
+	webviewBind = func(*Webview, uintptr, uintptr, unsafe.Pointer) { binds++ }
+
+	navigate := func() int {
+		wv := fakeWebview()
+		defer releaseWindow(wv)
+		bindCallbackMutex.Lock()
+		bindCallbackRegistry[wv] = map[string]bindEntry{"greet": {callback: 1}}
+		bindCallbackMutex.Unlock()
+		binds = 0
+		wv.installRuntimeScript()
+		wv.dispatchEvent(NavigationStartedEvent{URL: "wvapp://index.html/"})
+		wv.dispatchEvent(DomReadyEvent{})
+		return binds
+	}
+
+	// 原生库不支持扩展事件时只会收到 DomReady
+	webviewSetEventDataCallback = nil
+	if got := navigate(); got != 1 {
+		t.Fatalf("expected one rebind on DomReady, got %d", got)
+	}
+	// 支持 NavigationStarted 时只在导航开始时重新绑定
+	webviewSetEventDataCallback = func(*Webview, uintptr) {}
+	if got := navigate(); got != 1 {
+		t.Fatalf("expected one rebind per navigation, got %d", got)
+	}
+}
@@ -61,3 +61,7 @@ func loadSymbol(lib uintptr, name string) uintptr {
 	}
 	return ptr
 }
+
+func lookupSymbol(lib uintptr, name string) (uintptr, error) {
+	return purego.Dlsym(lib, name)
+}
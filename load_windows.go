@@ -41,3 +41,7 @@ func loadSymbol(lib uintptr, name string) uintptr {
 	}
 	return ptr
 }
+
+func lookupSymbol(lib uintptr, name string) (uintptr, error) {
+	return syscall.GetProcAddress(syscall.Handle(lib), name)
+}
@@ -0,0 +1,539 @@
+package wvapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"slices"
+	"sync"
+
+	"github.com/ebitengine/purego"
+)
+
+// MenuItemKind 菜单项类型
+type MenuItemKind int
+
+const (
+	MenuItemNormal MenuItemKind = iota
+	MenuItemSeparator
+	MenuItemSubmenu
+	MenuItemCheckbox
+	MenuItemRadio
+)
+
+func (k MenuItemKind) String() string {
+	switch k {
+	case MenuItemNormal:
+		return "normal"
+	case MenuItemSeparator:
+		return "separator"
+	case MenuItemSubmenu:
+		return "submenu"
+	case MenuItemCheckbox:
+		return "checkbox"
+	case MenuItemRadio:
+		return "radio"
+	}
+	return fmt.Sprintf("MenuItemKind(%d)", int(k))
+}
+
+// MenuClickEvent 菜单项被点击时传给 OnClick
+type MenuClickEvent struct {
+	Item   *MenuItem
+	Window *Webview // 触发点击的窗口，应用菜单在没有窗口聚焦时为 nil
+	Data   any      // ShowContextMenu 传入的数据，只附加给该次弹出菜单中的菜单项，其他菜单为 nil
+}
+
+// MenuItem 菜单项
+//
+// 菜单挂载后修改状态请使用 SetChecked/SetEnabled/SetLabel，它们会同步到原生菜单；
+// 直接修改字段只在下一次挂载时生效。同一个 MenuItem 只能属于一个菜单。
+type MenuItem struct {
+	ID          string // 自定义标识，用于 Menu.FindItem
+	Label       string
+	Kind        MenuItemKind
+	Accelerator string // 例如 "CmdOrCtrl+S"，见 ParseAccelerator
+	Disabled    bool
+	Checked     bool   // 仅对 checkbox/radio 有效
+	Group       string // radio 分组：同一菜单内同组的 radio 只有一个处于选中状态
+	Submenu     *Menu  // 设置后该项按子菜单处理
+	OnClick     func(ev MenuClickEvent)
+
+	nativeID uintptr
+	parent   *Menu
+}
+
+// Menu 菜单，用于应用菜单栏、窗口菜单栏和右键菜单
+type Menu struct {
+	Items []*MenuItem
+}
+
+// NewMenu 创建菜单
+func NewMenu(items ...*MenuItem) *Menu {
+	return &Menu{Items: items}
+}
+
+// Separator 创建分隔线
+func Separator() *MenuItem {
+	return &MenuItem{Kind: MenuItemSeparator}
+}
+
+// SubmenuItem 创建子菜单项
+func SubmenuItem(label string, items ...*MenuItem) *MenuItem {
+	return &MenuItem{Label: label, Kind: MenuItemSubmenu, Submenu: NewMenu(items...)}
+}
+
+// FindItem 按 ID 递归查找菜单项
+func (m *Menu) FindItem(id string) *MenuItem {
+	if m == nil {
+		return nil
+	}
+	for _, item := range m.Items {
+		if item == nil {
+			continue
+		}
+		if item.ID == id && id != "" {
+			return item
+		}
+		if found := item.Submenu.FindItem(id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// 已挂载菜单的位置，用于判断哪些菜单项仍然可能被点击
+type (
+	windowMenuSlot  struct{ w *Webview } // 窗口菜单栏，w 为 nil 时为应用菜单
+	contextMenuSlot struct{ w *Webview } // 窗口最近一次弹出的右键菜单
+	trayMenuSlot    struct{ id uintptr }
+)
+
+var (
+	menuMutex          sync.Mutex
+	menuItemByID       = make(map[uintptr]*MenuItem)
+	menuNextID         uintptr
+	mountedMenus       = make(map[any]*Menu)                 // 已挂载到原生库的菜单
+	contextMenus       = make(map[string]*Menu)              // 应用级右键菜单
+	windowContextMenus = make(map[*Webview]map[string]*Menu) // 窗口级右键菜单，优先于应用级
+	contextMenuData    = make(map[*Webview]any)              // 最近一次 ShowContextMenu 传入的数据
+	menuCallback       uintptr
+	menuCallbackOnce   sync.Once
+)
+
+// nativeMenuItem 传给原生库的菜单 JSON 结构
+type nativeMenuItem struct {
+	ID          uintptr          `json:"id,omitempty"`
+	Kind        string           `json:"kind"`
+	Label       string           `json:"label,omitempty"`
+	Accelerator string           `json:"accelerator,omitempty"`
+	Enabled     bool             `json:"enabled"`
+	Checked     bool             `json:"checked,omitempty"`
+	Submenu     []nativeMenuItem `json:"submenu,omitempty"`
+}
+
+// buildNativeMenu 校验菜单并转换为原生结构，同时为菜单项分配原生 ID
+func buildNativeMenu(m *Menu) ([]nativeMenuItem, error) {
+	menuMutex.Lock()
+	defer menuMutex.Unlock()
+	return buildNativeMenuLocked(m, make(map[*Menu]bool), make(map[*MenuItem]bool))
+}
+
+func buildNativeMenuLocked(m *Menu, visiting map[*Menu]bool, seen map[*MenuItem]bool) ([]nativeMenuItem, error) {
+	if m == nil {
+		return nil, nil
+	}
+	if visiting[m] {
+		return nil, fmt.Errorf("webview: menu contains itself")
+	}
+	visiting[m] = true
+	defer delete(visiting, m)
+
+	items := make([]nativeMenuItem, 0, len(m.Items))
+	for _, item := range m.Items {
+		if item == nil {
+			continue
+		}
+		// 菜单项的 parent 决定 radio 分组，不能同时属于两个菜单
+		if seen[item] || item.parent != nil && item.parent != m && slices.Contains(item.parent.Items, item) {
+			return nil, fmt.Errorf("webview: menu item %q is used in more than one menu", item.Label)
+		}
+		seen[item] = true
+		kind := item.Kind
+		if item.Submenu != nil {
+			kind = MenuItemSubmenu
+		}
+		if kind == MenuItemSeparator {
+			items = append(items, nativeMenuItem{Kind: kind.String()})
+			continue
+		}
+		if item.Label == "" {
+			return nil, fmt.Errorf("webview: %s menu item has no label", kind)
+		}
+		if kind < MenuItemNormal || kind > MenuItemRadio {
+			return nil, fmt.Errorf("webview: menu item %q has invalid kind %d", item.Label, int(kind))
+		}
+		if kind == MenuItemSubmenu && item.Submenu == nil {
+			return nil, fmt.Errorf("webview: submenu %q has no items", item.Label)
+		}
+		accelerator, err := nativeAccelerator(item.Accelerator)
+		if err != nil {
+			return nil, fmt.Errorf("menu item %q: %w", item.Label, err)
+		}
+		if kind == MenuItemSubmenu && accelerator != "" {
+			return nil, fmt.Errorf("webview: submenu %q cannot have an accelerator", item.Label)
+		}
+		if item.nativeID == 0 {
+			menuNextID++
+			item.nativeID = menuNextID
+		}
+		menuItemByID[item.nativeID] = item
+		item.parent = m
+		native := nativeMenuItem{
+			ID:          item.nativeID,
+			Kind:        kind.String(),
+			Label:       item.Label,
+			Accelerator: accelerator,
+			Enabled:     !item.Disabled,
+			Checked:     item.Checked && (kind == MenuItemCheckbox || kind == MenuItemRadio),
+		}
+		if kind == MenuItemSubmenu {
+			if native.Submenu, err = buildNativeMenuLocked(item.Submenu, visiting, seen); err != nil {
+				return nil, err
+			}
+		}
+		items = append(items, native)
+	}
+	return items, nil
+}
+
+// mountNativeMenu 将菜单登记到 slot 并返回原生 JSON，m 为 nil 时卸载该位置的菜单（返回 nil）
+func mountNativeMenu(slot any, m *Menu) ([]byte, error) {
+	menuMutex.Lock()
+	defer menuMutex.Unlock()
+	return mountNativeMenuLocked(slot, m)
+}
+
+func mountNativeMenuLocked(slot any, m *Menu) ([]byte, error) {
+	if m == nil {
+		delete(mountedMenus, slot)
+		pruneMenuItemsLocked()
+		return nil, nil
+	}
+	items, err := buildNativeMenuLocked(m, make(map[*Menu]bool), make(map[*MenuItem]bool))
+	if err != nil {
+		return nil, err
+	}
+	mountedMenus[slot] = m
+	pruneMenuItemsLocked()
+	return json.Marshal(items)
+}
+
+// pruneMenuItemsLocked 从 menuItemByID 中删除不再属于任何已挂载菜单的菜单项，
+// 它们再次挂载时会分配新的原生 ID
+func pruneMenuItemsLocked() {
+	reachable := make(map[*MenuItem]bool)
+	var walk func(*Menu)
+	walk = func(m *Menu) {
+		if m == nil {
+			return
+		}
+		for _, item := range m.Items {
+			if item != nil && !reachable[item] {
+				reachable[item] = true
+				walk(item.Submenu)
+			}
+		}
+	}
+	for _, m := range mountedMenus {
+		walk(m)
+	}
+	for id, item := range menuItemByID {
+		if !reachable[item] {
+			delete(menuItemByID, id)
+			item.nativeID = 0
+		}
+	}
+}
+
+// menuContainsLocked 判断菜单项是否属于 m（包括子菜单）
+func menuContainsLocked(m *Menu, target *MenuItem, visiting map[*Menu]bool) bool {
+	if m == nil || visiting[m] {
+		return false
+	}
+	visiting[m] = true
+	for _, item := range m.Items {
+		if item == target || item != nil && menuContainsLocked(item.Submenu, target, visiting) {
+			return true
+		}
+	}
+	return false
+}
+
+// menuItemUpdate 运行时同步到原生菜单的状态
+type menuItemUpdate struct {
+	id      uintptr
+	Label   string `json:"label"`
+	Enabled bool   `json:"enabled"`
+	Checked bool   `json:"checked"`
+}
+
+func (item *MenuItem) updateLocked() menuItemUpdate {
+	return menuItemUpdate{id: item.nativeID, Label: item.Label, Enabled: !item.Disabled, Checked: item.Checked}
+}
+
+// setCheckedLocked 修改选中状态，radio 选中时取消同组其他项，返回需要同步的项
+func (item *MenuItem) setCheckedLocked(checked bool) []menuItemUpdate {
+	var updates []menuItemUpdate
+	if checked && item.Kind == MenuItemRadio && item.parent != nil {
+		for _, sibling := range item.parent.Items {
+			if sibling != nil && sibling != item && sibling.Kind == MenuItemRadio && sibling.Group == item.Group && sibling.Checked {
+				sibling.Checked = false
+				updates = append(updates, sibling.updateLocked())
+			}
+		}
+	}
+	if item.Checked != checked {
+		item.Checked = checked
+		updates = append(updates, item.updateLocked())
+	}
+	return updates
+}
+
+// pushMenuUpdates 将状态变化同步给原生库，尚未挂载的菜单项只更新 Go 端状态
+func pushMenuUpdates(updates []menuItemUpdate) {
+	if webviewUpdateMenuItem == nil {
+		return
+	}
+	for _, u := range updates {
+		if u.id == 0 {
+			continue
+		}
+		data, err := json.Marshal(u)
+		if err != nil {
+			continue
+		}
+		id := u.id
+		mainScheduler.RunInMainThread(func() {
+			cstr, ptr := cString(string(data))
+			webviewUpdateMenuItem(id, ptr)
+			runtime.KeepAlive(cstr)
+		})
+	}
+}
+
+// SetChecked 修改 checkbox/radio 的选中状态
+func (item *MenuItem) SetChecked(checked bool) {
+	menuMutex.Lock()
+	updates := item.setCheckedLocked(checked)
+	menuMutex.Unlock()
+	pushMenuUpdates(updates)
+}
+
+// SetEnabled 启用或禁用菜单项
+func (item *MenuItem) SetEnabled(enabled bool) {
+	menuMutex.Lock()
+	changed := item.Disabled == enabled
+	item.Disabled = !enabled
+	update := item.updateLocked()
+	menuMutex.Unlock()
+	if changed {
+		pushMenuUpdates([]menuItemUpdate{update})
+	}
+}
+
+// SetLabel 修改菜单项文字
+func (item *MenuItem) SetLabel(label string) {
+	menuMutex.Lock()
+	changed := item.Label != label
+	item.Label = label
+	update := item.updateLocked()
+	menuMutex.Unlock()
+	if changed {
+		pushMenuUpdates([]menuItemUpdate{update})
+	}
+}
+
+// IsChecked 返回当前选中状态（包括用户点击造成的变化）
+func (item *MenuItem) IsChecked() bool {
+	menuMutex.Lock()
+	defer menuMutex.Unlock()
+	return item.Checked
+}
+
+// IsEnabled 返回菜单项是否可用
+func (item *MenuItem) IsEnabled() bool {
+	menuMutex.Lock()
+	defer menuMutex.Unlock()
+	return !item.Disabled
+}
+
+// handleMenuClick 处理原生菜单点击：先更新 checkbox/radio 状态，再在独立 goroutine 中调用 OnClick，
+// 因此 OnClick 中可以直接使用 MessageDialog 等阻塞 API
+func handleMenuClick(wv *Webview, id uintptr) {
+	menuMutex.Lock()
+	item := menuItemByID[id]
+	if item == nil || item.Disabled {
+		menuMutex.Unlock()
+		return
+	}
+	var updates []menuItemUpdate
+	switch item.Kind {
+	case MenuItemCheckbox:
+		updates = item.setCheckedLocked(!item.Checked)
+	case MenuItemRadio:
+		updates = item.setCheckedLocked(true)
+	}
+	// 只有最近一次弹出的右键菜单中的菜单项才携带数据，菜单被关闭而未点击时数据不会串到其他菜单
+	var data any
+	popup := contextMenuSlot{wv}
+	if menuContainsLocked(mountedMenus[popup], item, make(map[*Menu]bool)) {
+		data = contextMenuData[wv]
+		delete(contextMenuData, wv)
+		delete(mountedMenus, popup)
+	}
+	onClick := item.OnClick
+	menuMutex.Unlock()
+
+	pushMenuUpdates(updates)
+	if onClick == nil {
+		return
+	}
+	ev := MenuClickEvent{Item: item, Window: wv, Data: data}
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				slog.Error("Panic in menu click handler", "item", item.Label, "panic", r)
+			}
+		}()
+		onClick(ev)
+	}()
+}
+
+// cMenuClickHandler 原生菜单点击回调，wv 为触发窗口（可能为 nil）
+func cMenuClickHandler(wv *Webview, id uintptr) uintptr {
+	handleMenuClick(wv, id)
+	return 0
+}
+
+func installMenuCallback() {
+	menuCallbackOnce.Do(func() {
+		menuCallback = purego.NewCallback(cMenuClickHandler)
+		mainScheduler.RunInMainThread(func() { webviewSetMenuCallback(menuCallback) })
+	})
+}
+
+// setNativeMenu 将菜单挂载到窗口，w 为 nil 时设置应用菜单，menu 为 nil 时移除
+func setNativeMenu(w *Webview, menu *Menu) error {
+	if err := loadWebviewLibrary(); err != nil {
+		return err
+	}
+	if webviewSetMenu == nil || webviewSetMenuCallback == nil {
+		return ErrNotSupported
+	}
+	data, err := mountNativeMenu(windowMenuSlot{w}, menu)
+	if err != nil {
+		return err
+	}
+	installMenuCallback()
+	mainScheduler.RunInMainThread(func() {
+		if data == nil {
+			webviewSetMenu(w, 0)
+			return
+		}
+		cstr, ptr := cString(string(data))
+		webviewSetMenu(w, ptr)
+		runtime.KeepAlive(cstr)
+	})
+	return nil
+}
+
+// SetApplicationMenu 设置应用菜单：macOS 上为顶部菜单栏，其他平台作为没有自己菜单的窗口的菜单栏
+func SetApplicationMenu(menu *Menu) error {
+	return setNativeMenu(nil, menu)
+}
+
+// SetMenu 设置窗口自己的菜单栏，nil 表示移除（回到应用菜单）
+func (w *Webview) SetMenu(menu *Menu) error {
+	if w == nil {
+		return fmt.Errorf("webview: nil webview")
+	}
+	return setNativeMenu(w, menu)
+}
+
+// RegisterContextMenu 注册应用级右键菜单，可在任意窗口中通过名称弹出，menu 为 nil 时注销
+func RegisterContextMenu(name string, menu *Menu) {
+	menuMutex.Lock()
+	defer menuMutex.Unlock()
+	if menu == nil {
+		delete(contextMenus, name)
+		return
+	}
+	contextMenus[name] = menu
+}
+
+// SetContextMenu 注册仅在本窗口可用的右键菜单，同名时优先于应用级菜单
+func (w *Webview) SetContextMenu(name string, menu *Menu) {
+	menuMutex.Lock()
+	defer menuMutex.Unlock()
+	if menu == nil {
+		delete(windowContextMenus[w], name)
+		return
+	}
+	if windowContextMenus[w] == nil {
+		windowContextMenus[w] = make(map[string]*Menu)
+	}
+	windowContextMenus[w][name] = menu
+}
+
+func (w *Webview) lookupContextMenu(name string) *Menu {
+	menuMutex.Lock()
+	defer menuMutex.Unlock()
+	if menu, ok := windowContextMenus[w][name]; ok {
+		return menu
+	}
+	return contextMenus[name]
+}
+
+// releaseMenus 窗口关闭时卸载其菜单栏与右键菜单
+func releaseMenus(wv *Webview) {
+	menuMutex.Lock()
+	defer menuMutex.Unlock()
+	delete(windowContextMenus, wv)
+	delete(contextMenuData, wv)
+	delete(mountedMenus, windowMenuSlot{wv})
+	delete(mountedMenus, contextMenuSlot{wv})
+	pruneMenuItemsLocked()
+}
+
+// ShowContextMenu 在窗口内容区坐标 (x, y) 弹出已注册的右键菜单，data 会传给该菜单中菜单项的 OnClick
+func (w *Webview) ShowContextMenu(name string, x, y int, data any) error {
+	menu := w.lookupContextMenu(name)
+	if menu == nil {
+		return fmt.Errorf("webview: context menu %q is not registered", name)
+	}
+	if err := loadWebviewLibrary(); err != nil {
+		return err
+	}
+	if webviewShowContextMenu == nil || webviewSetMenuCallback == nil {
+		return ErrNotSupported
+	}
+	menuMutex.Lock()
+	payload, err := mountNativeMenuLocked(contextMenuSlot{w}, menu)
+	if err == nil {
+		contextMenuData[w] = data
+	}
+	menuMutex.Unlock()
+	if err != nil {
+		return err
+	}
+	installMenuCallback()
+
+	mainScheduler.RunInMainThread(func() {
+		cstr, ptr := cString(string(payload))
+		webviewShowContextMenu(w, ptr, x, y)
+		runtime.KeepAlive(cstr)
+	})
+	return nil
+}
@@ -0,0 +1,210 @@
+package wvapp
+
+import (
+	"encoding/json"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildNativeMenu(t *testing.T) {
+	save := &MenuItem{ID: "save", Label: "Save", Accelerator: "CmdOrCtrl+S"}
+	menu := NewMenu(
+		SubmenuItem("File",
+			save,
+			Separator(),
+			&MenuItem{Label: "Auto Save", Kind: MenuItemCheckbox, Checked: true},
+		),
+		SubmenuItem("View",
+			&MenuItem{ID: "light", Label: "Light", Kind: MenuItemRadio, Group: "theme", Checked: true},
+			&MenuItem{ID: "dark", Label: "Dark", Kind: MenuItemRadio, Group: "theme", Disabled: true},
+		),
+	)
+	items, err := buildNativeMenu(menu)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 || items[0].Kind != "submenu" || len(items[0].Submenu) != 3 {
+		t.Fatalf("unexpected menu structure: %+v", items)
+	}
+	file := items[0].Submenu
+	wantAccel := "Ctrl+S"
+	if runtime.GOOS == "darwin" {
+		wantAccel = "Cmd+S"
+	}
+	if file[0].ID == 0 || file[0].Accelerator != wantAccel || !file[0].Enabled {
+		t.Fatalf("save item = %+v", file[0])
+	}
+	if file[1].Kind != "separator" || file[1].ID != 0 {
+		t.Fatalf("separator = %+v", file[1])
+	}
+	if !file[2].Checked || file[2].Kind != "checkbox" {
+		t.Fatalf("checkbox = %+v", file[2])
+	}
+	if view := items[1].Submenu; view[1].Enabled {
+		t.Fatalf("disabled item should be sent as disabled: %+v", view[1])
+	}
+
+	// 重复挂载时 ID 保持不变
+	again, _ := buildNativeMenu(menu)
+	if again[0].Submenu[0].ID != file[0].ID {
+		t.Fatalf("native ID changed between builds")
+	}
+	if menu.FindItem("save") != save || menu.FindItem("missing") != nil {
+		t.Fatalf("FindItem failed")
+	}
+	data, err := json.Marshal(items)
+	if err != nil || !strings.Contains(string(data), `"label":"Auto Save"`) {
+		t.Fatalf("marshal = %s, %v", data, err)
+	}
+}
+
+func TestBuildNativeMenuErrors(t *testing.T) {
+	loop := NewMenu()
+	loop.Items = append(loop.Items, &MenuItem{Label: "Loop", Submenu: loop})
+
+	tests := map[string]*Menu{
+		"missing label": NewMenu(&MenuItem{}),
+		"bad accel":     NewMenu(&MenuItem{Label: "Quit", Accelerator: "Ctrl+Nope"}),
+		"empty submenu": NewMenu(&MenuItem{Label: "File", Kind: MenuItemSubmenu}),
+		"submenu accel": NewMenu(&MenuItem{Label: "File", Accelerator: "Ctrl+F", Submenu: NewMenu()}),
+		"invalid kind":  NewMenu(&MenuItem{Label: "X", Kind: MenuItemKind(42)}),
+		"cycle":         loop,
+	}
+	for name, menu := range tests {
+		if _, err := buildNativeMenu(menu); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestMenuClickState(t *testing.T) {
+	clicked := make(chan MenuClickEvent, 1)
+	autoSave := &MenuItem{Label: "Auto Save", Kind: MenuItemCheckbox}
+	light := &MenuItem{Label: "Light", Kind: MenuItemRadio, Group: "theme", Checked: true}
+	dark := &MenuItem{Label: "Dark", Kind: MenuItemRadio, Group: "theme", OnClick: func(ev MenuClickEvent) { clicked <- ev }}
+	other := &MenuItem{Label: "Compact", Kind: MenuItemRadio, Group: "density", Checked: true}
+	disabled := &MenuItem{Label: "Never", Disabled: true, OnClick: func(MenuClickEvent) { t.Error("disabled item clicked") }}
+	menu := NewMenu(autoSave, light, dark, other, disabled)
+	if _, err := buildNativeMenu(menu); err != nil {
+		t.Fatal(err)
+	}
+
+	wv := fakeWebview()
+	handleMenuClick(wv, autoSave.nativeID)
+	if !autoSave.IsChecked() {
+		t.Fatal("checkbox should toggle on click")
+	}
+	handleMenuClick(wv, dark.nativeID)
+	if !dark.IsChecked() || light.IsChecked() || !other.IsChecked() {
+		t.Fatalf("radio group not updated: light=%v dark=%v other=%v", light.IsChecked(), dark.IsChecked(), other.IsChecked())
+	}
+	select {
+	case ev := <-clicked:
+		if ev.Item != dark || ev.Window != wv {
+			t.Fatalf("click event = %+v", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("OnClick not called")
+	}
+	handleMenuClick(wv, disabled.nativeID)
+	handleMenuClick(wv, 0)
+
+	light.SetChecked(true)
+	if dark.IsChecked() {
+		t.Fatal("SetChecked on a radio item should uncheck its group")
+	}
+	dark.SetEnabled(false)
+	if dark.IsEnabled() {
+		t.Fatal("SetEnabled(false) had no effect")
+	}
+}
+
+func TestContextMenuLookup(t *testing.T) {
+	wv := fakeWebview()
+	appMenu := NewMenu(&MenuItem{Label: "Copy"})
+	windowMenu := NewMenu(&MenuItem{Label: "Rename"})
+
+	RegisterContextMenu("item", appMenu)
+	defer RegisterContextMenu("item", nil)
+	if wv.lookupContextMenu("item") != appMenu {
+		t.Fatal("app-wide context menu not found")
+	}
+	wv.SetContextMenu("item", windowMenu)
+	if wv.lookupContextMenu("item") != windowMenu {
+		t.Fatal("window context menu should override the app-wide one")
+	}
+	if fakeWebview().lookupContextMenu("item") != appMenu {
+		t.Fatal("window context menu leaked to other windows")
+	}
+	releaseWindow(wv)
+	if wv.lookupContextMenu("item") != appMenu {
+		t.Fatal("window context menus should be released with the window")
+	}
+	if err := wv.ShowContextMenu("missing", 0, 0, nil); err == nil {
+		t.Fatal("unknown context menu should be an error")
+	}
+}
+
+func TestContextMenuDataOnlyForPopupItems(t *testing.T) {
+	wv := fakeWebview()
+	defer releaseMenus(wv)
+	clicked := make(chan MenuClickEvent, 2)
+	onClick := func(ev MenuClickEvent) { clicked <- ev }
+	rename := &MenuItem{Label: "Rename", OnClick: onClick}
+	save := &MenuItem{Label: "Save", OnClick: onClick}
+	if _, err := mountNativeMenu(windowMenuSlot{wv}, NewMenu(save)); err != nil {
+		t.Fatal(err)
+	}
+
+	// 弹出右键菜单后未点击就关闭，随后点击菜单栏
+	menuMutex.Lock()
+	_, err := mountNativeMenuLocked(contextMenuSlot{wv}, NewMenu(rename))
+	contextMenuData[wv] = "row-7"
+	menuMutex.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	handleMenuClick(wv, save.nativeID)
+	if ev := <-clicked; ev.Item != save || ev.Data != nil {
+		t.Fatalf("menu bar click = %+v, want no data", ev)
+	}
+	handleMenuClick(wv, rename.nativeID)
+	if ev := <-clicked; ev.Item != rename || ev.Data != "row-7" {
+		t.Fatalf("context menu click = %+v, want row-7", ev)
+	}
+}
+
+func TestMenuItemsReleased(t *testing.T) {
+	wv := fakeWebview()
+	defer releaseMenus(wv)
+	old := &MenuItem{Label: "Old"}
+	if _, err := mountNativeMenu(windowMenuSlot{wv}, NewMenu(old)); err != nil {
+		t.Fatal(err)
+	}
+	id := old.nativeID
+	if _, err := mountNativeMenu(windowMenuSlot{wv}, NewMenu(&MenuItem{Label: "New"})); err != nil {
+		t.Fatal(err)
+	}
+	menuMutex.Lock()
+	_, leaked := menuItemByID[id]
+	menuMutex.Unlock()
+	if leaked || old.nativeID != 0 {
+		t.Fatal("replaced menu items should be released")
+	}
+}
+
+func TestSharedMenuItemRejected(t *testing.T) {
+	shared := &MenuItem{Label: "Dark", Kind: MenuItemRadio, Group: "theme"}
+	if _, err := buildNativeMenu(NewMenu(shared)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := buildNativeMenu(NewMenu(shared)); err == nil {
+		t.Fatal("an item in two menus should be rejected")
+	}
+	twice := &MenuItem{Label: "Light"}
+	if _, err := buildNativeMenu(NewMenu(twice, twice)); err == nil {
+		t.Fatal("an item listed twice should be rejected")
+	}
+}
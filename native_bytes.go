@@ -0,0 +1,47 @@
+package wvapp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"unsafe"
+
+	"github.com/ebitengine/purego"
+)
+
+// bytesResult 原生库通过回调返回的二进制数据
+type bytesResult struct {
+	data []byte
+	err  string
+}
+
+var (
+	bytesCallback     uintptr
+	bytesCallbackOnce sync.Once
+	bytesRequests     nativeRequests[bytesResult]
+)
+
+// cBytesHandler 异步原生请求（PDF 导出、截图）完成时回调，data 只在回调期间有效；errMsg 非空表示失败
+func cBytesHandler(id uintptr, data *byte, length uint64, errMsg uintptr) uintptr {
+	res := bytesResult{err: goString(errMsg)}
+	if data != nil && length > 0 {
+		res.data = bytes.Clone(unsafe.Slice(data, length))
+	}
+	bytesRequests.resolve(id, res)
+	return 0
+}
+
+// requestBytes 在主线程中调用 start 发起异步原生请求，然后等待回调结果；
+// 主线程任务只负责发起请求，渲染期间不会占住调度器
+func requestBytes(ctx context.Context, start func(callback, id uintptr)) ([]byte, error) {
+	bytesCallbackOnce.Do(func() { bytesCallback = purego.NewCallback(cBytesHandler) })
+	res, err := bytesRequests.do(ctx, func(id uintptr) { start(bytesCallback, id) })
+	if err != nil {
+		return nil, err
+	}
+	if res.err != "" {
+		return nil, errors.New("webview: " + res.err)
+	}
+	return res.data, nil
+}
@@ -0,0 +1,42 @@
+package wvapp
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"unsafe"
+)
+
+func TestBytesCallback(t *testing.T) {
+	pdf := []byte("%PDF-1.7\n...")
+	id, ch := bytesRequests.add()
+	cBytesHandler(id, unsafe.SliceData(pdf), uint64(len(pdf)), 0)
+	pdf[0] = 'X' // 回调返回后原生内存可能被释放，结果必须是副本
+	res, err := bytesRequests.wait(context.Background(), id, ch)
+	if err != nil || string(res.data) != "%PDF-1.7\n..." || res.err != "" {
+		t.Fatalf("bytes result = %q, %q, %v", res.data, res.err, err)
+	}
+}
+
+func TestRequestBytesError(t *testing.T) {
+	done := make(chan error, 1)
+	go func() {
+		_, err := requestBytes(context.Background(), func(_, id uintptr) {
+			bytesRequests.resolve(id, bytesResult{err: "page is not loaded"})
+		})
+		done <- err
+	}()
+	// 测试中没有运行主循环，手动执行主线程任务
+	for {
+		mainScheduler.PollTasks()
+		select {
+		case err := <-done:
+			if err == nil || !strings.Contains(err.Error(), "page is not loaded") {
+				t.Fatalf("error = %v", err)
+			}
+			return
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
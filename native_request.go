@@ -0,0 +1,70 @@
+package wvapp
+
+import (
+	"context"
+	"sync"
+)
+
+// nativeRequests 异步原生请求的登记表：发起时分配 ID，原生回调按 ID 送回结果。
+// PDF 导出、截图、消息框与数据操作共用这套机制，只是回调签名与结果类型不同。
+type nativeRequests[T any] struct {
+	mu      sync.Mutex
+	nextID  uintptr
+	pending map[uintptr]chan T
+}
+
+// add 登记一个新请求，返回其 ID 与容量为 1 的结果通道
+func (r *nativeRequests[T]) add() (uintptr, chan T) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.pending == nil {
+		r.pending = make(map[uintptr]chan T)
+	}
+	r.nextID++
+	ch := make(chan T, 1)
+	r.pending[r.nextID] = ch
+	return r.nextID, ch
+}
+
+// resolve 在原生回调中送回结果；请求已放弃或 ID 未知时忽略并返回 false，不会阻塞
+func (r *nativeRequests[T]) resolve(id uintptr, result T) bool {
+	r.mu.Lock()
+	ch := r.pending[id]
+	delete(r.pending, id)
+	r.mu.Unlock()
+	if ch == nil {
+		return false
+	}
+	ch <- result
+	return true
+}
+
+// wait 等待结果；ctx 结束时放弃请求，之后到达的回调会被忽略
+func (r *nativeRequests[T]) wait(ctx context.Context, id uintptr, ch chan T) (T, error) {
+	select {
+	case res := <-ch:
+		return res, nil
+	case <-ctx.Done():
+		r.mu.Lock()
+		delete(r.pending, id)
+		r.mu.Unlock()
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// isPending 请求是否仍在等待回调
+func (r *nativeRequests[T]) isPending(id uintptr) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.pending[id]
+	return ok
+}
+
+// do 登记请求，在主线程中调用 start 发起，然后等待回调结果；
+// 主线程任务只负责发起请求，等待期间不会占住调度器
+func (r *nativeRequests[T]) do(ctx context.Context, start func(id uintptr)) (T, error) {
+	id, ch := r.add()
+	mainScheduler.RunInMainThread(func() { start(id) })
+	return r.wait(ctx, id, ch)
+}
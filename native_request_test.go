@@ -0,0 +1,40 @@
+package wvapp
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNativeRequests(t *testing.T) {
+	var requests nativeRequests[string]
+
+	first, ch := requests.add()
+	second, _ := requests.add()
+	if first == second {
+		t.Fatal("request IDs must be unique")
+	}
+	if !requests.resolve(first, "done") {
+		t.Fatal("pending request not resolved")
+	}
+	if got, err := requests.wait(context.Background(), first, ch); err != nil || got != "done" {
+		t.Fatalf("wait = %q, %v", got, err)
+	}
+	// 重复或未知 ID 的回调被忽略，不会阻塞
+	if requests.resolve(first, "again") || requests.resolve(9999, "unknown") {
+		t.Fatal("unknown request should be ignored")
+	}
+
+	// ctx 结束后放弃请求，之后到达的回调被忽略
+	id, ch := requests.add()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := requests.wait(ctx, id, ch); err != context.Canceled {
+		t.Fatalf("error = %v, want context.Canceled", err)
+	}
+	if requests.isPending(id) {
+		t.Fatal("canceled request is still pending")
+	}
+	if requests.resolve(id, "late") {
+		t.Fatal("late callback should be ignored")
+	}
+}
@@ -0,0 +1,322 @@
+package wvapp
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/ebitengine/purego"
+)
+
+// NavigationDecision 导航决策
+type NavigationDecision int32
+
+const (
+	NavigationAllow        NavigationDecision = iota // 允许在当前窗口中加载
+	NavigationDeny                                   // 拒绝加载
+	NavigationOpenExternal                           // 拒绝加载，并在系统默认浏览器中打开
+)
+
+func (d NavigationDecision) String() string {
+	switch d {
+	case NavigationAllow:
+		return "allow"
+	case NavigationDeny:
+		return "deny"
+	case NavigationOpenExternal:
+		return "open-external"
+	}
+	return fmt.Sprintf("NavigationDecision(%d)", int32(d))
+}
+
+// NavigateHandler 导航回调，在主线程中同步调用
+type NavigateHandler func(url string) NavigationDecision
+
+// NavigationPolicy 窗口导航白名单
+//
+// AllowedURLs 中的模式形如 "scheme://host[:port]/path"，scheme、主机与路径分别匹配：
+// 主机中的 "*" 只匹配完整的域名标签（开头的 "*" 匹配一个或多个标签），
+// 路径中的 "*" 只匹配路径内的字符；模式路径不含 "?" 时不限制查询参数。
+// 例如 "https://example.com/*"、"https://*.example.com/*"。
+// 不含 "://" 的模式（如 "mailto:*"）只匹配没有主机部分的 URL。
+// 已注册的全局 URI scheme 以及 about:blank 总是允许。
+type NavigationPolicy struct {
+	AllowedURLs  []string
+	OpenExternal bool // 不在白名单中的 http/https/mailto 链接在系统浏览器中打开，而不是直接拒绝
+}
+
+// Evaluate 计算 rawURL 的导航决策，nil 策略允许所有导航
+func (p *NavigationPolicy) Evaluate(rawURL string) NavigationDecision {
+	if p == nil {
+		return NavigationAllow
+	}
+	if rawURL == "about:blank" {
+		return NavigationAllow
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme == "" {
+		return NavigationDeny
+	}
+	scheme := strings.ToLower(u.Scheme)
+	if name, ok := registeredScheme.Load().(string); ok && name != "" && strings.EqualFold(scheme, name) {
+		return NavigationAllow
+	}
+	for _, pattern := range p.AllowedURLs {
+		if matchURLPattern(pattern, u) {
+			return NavigationAllow
+		}
+	}
+	if p.OpenExternal && isExternalScheme(scheme) {
+		return NavigationOpenExternal
+	}
+	return NavigationDeny
+}
+
+func isExternalScheme(scheme string) bool {
+	switch scheme {
+	case "http", "https", "mailto":
+		return true
+	}
+	return false
+}
+
+// matchURLPattern 分别匹配 scheme、主机与路径，避免 "*" 跨越 URL 的组成部分
+func matchURLPattern(pattern string, u *url.URL) bool {
+	scheme := strings.ToLower(u.Scheme)
+	sep := strings.Index(pattern, "://")
+	if sep < 0 {
+		// 不透明 URL，如 mailto:someone@example.com
+		if u.Host != "" || u.Opaque == "" {
+			return false
+		}
+		return matchWildcard(pattern, scheme+":"+u.Opaque)
+	}
+	if !strings.EqualFold(pattern[:sep], scheme) || u.Host == "" {
+		return false
+	}
+	// 没有路径的模式（https://example.com）表示整个站点
+	hostPattern, pathPattern := pattern[sep+3:], "/*"
+	if i := strings.Index(hostPattern, "/"); i >= 0 {
+		hostPattern, pathPattern = hostPattern[:i], hostPattern[i:]
+	}
+	if !matchHost(strings.ToLower(hostPattern), strings.ToLower(u.Host)) {
+		return false
+	}
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if strings.Contains(pathPattern, "?") {
+		return matchWildcard(pathPattern, path+"?"+u.RawQuery)
+	}
+	// "/docs/*" 同时匹配目录本身 "/docs"
+	if dir, ok := strings.CutSuffix(pathPattern, "/*"); ok && path == dir {
+		return true
+	}
+	return matchWildcard(pathPattern, path)
+}
+
+// matchHost 按域名标签匹配 host[:port]，"*" 标签不会匹配 "."、"/" 等分隔符
+func matchHost(pattern, host string) bool {
+	pattern, patternPort := splitHostPort(pattern)
+	host, port := splitHostPort(host)
+	if patternPort != port && patternPort != "*" {
+		return false
+	}
+	if pattern == host {
+		return true
+	}
+	want := strings.Split(pattern, ".")
+	got := strings.Split(host, ".")
+	if want[0] == "*" {
+		// 开头的 "*" 匹配一个或多个标签
+		if len(got) <= len(want)-1 {
+			return false
+		}
+		want, got = want[1:], got[len(got)-len(want)+1:]
+	}
+	if len(want) != len(got) {
+		return false
+	}
+	for i, label := range want {
+		if label != got[i] && (label != "*" || got[i] == "") {
+			return false
+		}
+	}
+	return true
+}
+
+// splitHostPort 拆分 host[:port]，兼容 IPv6 字面量
+func splitHostPort(hostport string) (host, port string) {
+	i := strings.LastIndex(hostport, ":")
+	if i < 0 || strings.Contains(hostport[i:], "]") {
+		return hostport, ""
+	}
+	return hostport[:i], hostport[i+1:]
+}
+
+// matchWildcard 简单通配匹配，"*" 匹配任意长度的任意字符
+func matchWildcard(pattern, s string) bool {
+	star, next := -1, 0
+	p, i := 0, 0
+	for i < len(s) {
+		switch {
+		case p < len(pattern) && pattern[p] == '*':
+			star, next = p, i
+			p++
+		case p < len(pattern) && pattern[p] == s[i]:
+			p++
+			i++
+		case star >= 0:
+			p = star + 1
+			next++
+			i = next
+		default:
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
+
+// OpenExternal 在系统默认浏览器或邮件客户端中打开链接（仅支持 http/https/mailto）
+func OpenExternal(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+	if !isExternalScheme(strings.ToLower(u.Scheme)) {
+		return fmt.Errorf("webview: refusing to open %q externally", u.Scheme)
+	}
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", u.String())
+	case "darwin":
+		cmd = exec.Command("open", u.String())
+	default:
+		cmd = exec.Command("xdg-open", u.String())
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go cmd.Wait() //nolint:errcheck
+	return nil
+}
+
+type navigationState struct {
+	policy    *NavigationPolicy
+	handler   NavigateHandler
+	installed bool
+}
+
+var (
+	navigationRegistry     = make(map[*Webview]*navigationState)
+	navigationMutex        sync.Mutex
+	navigationCallback     uintptr
+	navigationCallbackOnce sync.Once
+)
+
+// cNavigationHandler 原生导航回调：返回 0 允许加载，非 0 拒绝
+func cNavigationHandler(wv *Webview, urlPtr uintptr) uintptr {
+	rawURL := goString(urlPtr)
+	decision := wv.evaluateNavigation(rawURL)
+	switch decision {
+	case NavigationAllow:
+		return 0
+	case NavigationOpenExternal:
+		if err := OpenExternal(rawURL); err != nil {
+			slog.Error("Failed to open external URL", "url", rawURL, "error", err)
+		}
+	}
+	slog.Debug("Navigation blocked", "url", rawURL, "decision", decision)
+	return 1
+}
+
+// evaluateNavigation 计算导航决策：OnNavigate 回调优先，未设置回调时使用白名单策略
+func (w *Webview) evaluateNavigation(rawURL string) NavigationDecision {
+	navigationMutex.Lock()
+	state := navigationRegistry[w]
+	navigationMutex.Unlock()
+	if state == nil {
+		return NavigationAllow
+	}
+	if state.handler != nil {
+		return state.handler(rawURL)
+	}
+	return state.policy.Evaluate(rawURL)
+}
+
+// SetNavigationPolicy 设置窗口导航白名单，nil 表示不限制
+func (w *Webview) SetNavigationPolicy(policy *NavigationPolicy) error {
+	var p *NavigationPolicy
+	if policy != nil {
+		p = &NavigationPolicy{
+			AllowedURLs:  append([]string(nil), policy.AllowedURLs...),
+			OpenExternal: policy.OpenExternal,
+		}
+	}
+	return w.updateNavigation(func(state *navigationState) { state.policy = p })
+}
+
+// OnNavigate 注册导航回调，回调的返回值优先于 NavigationPolicy；
+// 回调中可调用 policy.Evaluate(url) 复用白名单判断。
+func (w *Webview) OnNavigate(handler NavigateHandler) error {
+	return w.updateNavigation(func(state *navigationState) { state.handler = handler })
+}
+
+func (w *Webview) updateNavigation(update func(*navigationState)) error {
+	if webviewSetNavigationCallback == nil {
+		return ErrNotSupported
+	}
+	navigationMutex.Lock()
+	state, ok := navigationRegistry[w]
+	if !ok {
+		state = &navigationState{}
+		navigationRegistry[w] = state
+	}
+	update(state)
+	install := !state.installed
+	state.installed = true
+	navigationMutex.Unlock()
+
+	if install {
+		navigationCallbackOnce.Do(func() { navigationCallback = purego.NewCallback(cNavigationHandler) })
+		mainScheduler.RunInMainThread(func() { webviewSetNavigationCallback(w, navigationCallback) })
+	}
+	return nil
+}
+
+func (w *Webview) applyNavigationOptions(options *WindowOptions) error {
+	var errs []error
+	if options.NavigationPolicy != nil {
+		errs = append(errs, w.SetNavigationPolicy(options.NavigationPolicy))
+	}
+	if options.OnNavigate != nil {
+		errs = append(errs, w.OnNavigate(options.OnNavigate))
+	}
+	if options.ContentSecurityPolicy != "" {
+		errs = append(errs, w.SetContentSecurityPolicy(options.ContentSecurityPolicy))
+	}
+	return errors.Join(errs...)
+}
+
+// SetContentSecurityPolicy 为该窗口中由全局 URI scheme 提供的页面设置 CSP 响应头
+func (w *Webview) SetContentSecurityPolicy(csp string) error {
+	if webviewSetContentSecurityPolicy == nil {
+		return ErrNotSupported
+	}
+	mainScheduler.RunInMainThread(func() {
+		cstr, ptr := cString(csp)
+		webviewSetContentSecurityPolicy(w, ptr)
+		runtime.KeepAlive(cstr)
+	})
+	return nil
+}
@@ -0,0 +1,162 @@
+package wvapp
+
+import (
+	"net/url"
+	"testing"
+	"unsafe"
+)
+
+// fakeWebview 返回一个仅用作 map 键的伪窗口句柄（每次调用地址不同）
+func fakeWebview() *Webview {
+	return (*Webview)(unsafe.Pointer(new(int64)))
+}
+
+func TestNavigationPolicyEvaluate(t *testing.T) {
+	registeredScheme.Store("wvapp")
+	defer registeredScheme.Store("")
+
+	policy := &NavigationPolicy{
+		AllowedURLs: []string{"https://example.com/*", "https://*.docs.example.org/*"},
+	}
+	cases := []struct {
+		url  string
+		want NavigationDecision
+	}{
+		{"about:blank", NavigationAllow},
+		{"wvapp://index.html/", NavigationAllow},
+		{"WVAPP://index.html/settings", NavigationAllow},
+		{"https://example.com/", NavigationAllow},
+		{"https://EXAMPLE.com/path?q=1", NavigationAllow},
+		{"https://example.com.evil.net/", NavigationDeny},
+		{"http://example.com/", NavigationDeny},
+		{"https://api.docs.example.org/v1", NavigationAllow},
+		{"https://a.b.docs.example.org/", NavigationAllow},
+		{"https://docs.example.org/", NavigationDeny},
+		// "*" 不能跨越主机、路径与查询参数
+		{"https://evil.com/.docs.example.org/", NavigationDeny},
+		{"https://evil.com/?.docs.example.org/x", NavigationDeny},
+		{"https://evil.com/#.docs.example.org/x", NavigationDeny},
+		{"https://evil.com/x/.docs.example.org/", NavigationDeny},
+		{"https://example.com@evil.com/", NavigationDeny},
+		{"https://evil.com/https://example.com/", NavigationDeny},
+		{"https://example.com:8443/", NavigationDeny},
+		{"https://other.net/", NavigationDeny},
+		{"file:///etc/passwd", NavigationDeny},
+		{"javascript:alert(1)", NavigationDeny},
+		{"not a url", NavigationDeny},
+	}
+	for _, c := range cases {
+		if got := policy.Evaluate(c.url); got != c.want {
+			t.Errorf("Evaluate(%q) = %v, want %v", c.url, got, c.want)
+		}
+	}
+
+	policy.OpenExternal = true
+	if got := policy.Evaluate("https://other.net/"); got != NavigationOpenExternal {
+		t.Errorf("expected external link to open in system browser, got %v", got)
+	}
+	if got := policy.Evaluate("mailto:someone@example.com"); got != NavigationOpenExternal {
+		t.Errorf("expected mailto to open externally, got %v", got)
+	}
+	if got := policy.Evaluate("file:///etc/passwd"); got != NavigationDeny {
+		t.Errorf("file URLs must never be opened externally, got %v", got)
+	}
+
+	var none *NavigationPolicy
+	if got := none.Evaluate("https://anything/"); got != NavigationAllow {
+		t.Errorf("nil policy should allow navigation, got %v", got)
+	}
+}
+
+func TestMatchWildcard(t *testing.T) {
+	cases := []struct {
+		pattern, s string
+		want       bool
+	}{
+		{"*", "", true},
+		{"https://a.com/*", "https://a.com/", true},
+		{"https://a.com/*", "https://a.com", false},
+		{"https://*.a.com/*", "https://x.y.a.com/z", true},
+		{"https://*.a.com/*", "https://a.com/z", false},
+		{"*.png", "https://a.com/x.png", true},
+		{"exact", "exact", true},
+		{"exact", "exactly", false},
+	}
+	for _, c := range cases {
+		if got := matchWildcard(c.pattern, c.s); got != c.want {
+			t.Errorf("matchWildcard(%q, %q) = %v, want %v", c.pattern, c.s, got, c.want)
+		}
+	}
+}
+
+func TestMatchURLPattern(t *testing.T) {
+	cases := []struct {
+		pattern, url string
+		want         bool
+	}{
+		{"https://*.example.com/*", "https://app.example.com/a/b", true},
+		{"https://*.example.com/*", "https://evil.com/.example.com/", false},
+		{"https://*.example.com/*", "https://evil.com/?.example.com/x", false},
+		{"https://*.example.com/*", "https://evilexample.com/", false},
+		{"https://app.*.example.com/*", "https://app.eu.example.com/", true},
+		{"https://app.*.example.com/*", "https://app.a.b.example.com/", false},
+		{"https://app-*.example.com/*", "https://app-1.example.com/", false},
+		{"http://localhost:*/*", "http://localhost:5173/", true},
+		{"http://localhost:8080/*", "http://localhost:5173/", false},
+		{"http://[::1]:3000/*", "http://[::1]:3000/x", true},
+		{"https://example.com", "https://example.com", true},
+		{"https://example.com", "https://example.com/", true},
+		{"https://example.com", "HTTPS://EXAMPLE.COM/", true},
+		{"https://example.com", "https://example.com/x?q=1", true},
+		{"https://example.com", "https://example.com.evil.net/", false},
+		{"https://example.com/", "https://example.com", true},
+		{"https://example.com/docs/*", "https://example.com/docs", true},
+		{"https://example.com/docs/*", "https://example.com/docs/", true},
+		{"https://example.com/docs/*", "https://example.com/docsx", false},
+		{"https://example.com/docs/*", "https://example.com/doc", false},
+		{"https://example.com/docs/*", "https://example.com/blog/?/docs/", false},
+		{"https://example.com/search?q=*", "https://example.com/search?q=go", true},
+		{"HTTPS://Example.com/*", "https://EXAMPLE.COM/", true},
+		{"mailto:*", "mailto:someone@example.com", true},
+		{"mailto:*", "https://example.com/", false},
+	}
+	for _, c := range cases {
+		u, err := url.Parse(c.url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := matchURLPattern(c.pattern, u); got != c.want {
+			t.Errorf("matchURLPattern(%q, %q) = %v, want %v", c.pattern, c.url, got, c.want)
+		}
+	}
+}
+
+func TestEvaluateNavigationHandlerOverridesPolicy(t *testing.T) {
+	wv := fakeWebview()
+	defer releaseWindow(wv)
+
+	if got := wv.evaluateNavigation("https://any.example/"); got != NavigationAllow {
+		t.Fatalf("window without policy should allow navigation, got %v", got)
+	}
+
+	navigationMutex.Lock()
+	navigationRegistry[wv] = &navigationState{policy: &NavigationPolicy{AllowedURLs: []string{"https://ok.example/*"}}}
+	navigationMutex.Unlock()
+	if got := wv.evaluateNavigation("https://blocked.example/"); got != NavigationDeny {
+		t.Fatalf("expected policy to deny, got %v", got)
+	}
+
+	var seen string
+	navigationMutex.Lock()
+	navigationRegistry[wv].handler = func(url string) NavigationDecision {
+		seen = url
+		return NavigationAllow
+	}
+	navigationMutex.Unlock()
+	if got := wv.evaluateNavigation("https://blocked.example/"); got != NavigationAllow {
+		t.Fatalf("expected OnNavigate handler to override policy, got %v", got)
+	}
+	if seen != "https://blocked.example/" {
+		t.Fatalf("handler received %q", seen)
+	}
+}
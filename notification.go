@@ -0,0 +1,205 @@
+package wvapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"sync"
+
+	"github.com/ebitengine/purego"
+)
+
+// NotificationAction 通知上的按钮
+type NotificationAction struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+}
+
+// Notification 桌面通知
+type Notification struct {
+	ID      string               `json:"id,omitempty"` // 为空时自动生成；与已显示通知相同时替换该通知
+	Title   string               `json:"title"`
+	Body    string               `json:"body,omitempty"`
+	Icon    []byte               `json:"icon,omitempty"` // PNG 数据，为空时使用应用图标
+	Actions []NotificationAction `json:"actions,omitempty"`
+
+	OnClick  func()                `json:"-"` // 用户点击通知本身
+	OnAction func(actionID string) `json:"-"` // 用户点击 Actions 中的按钮
+}
+
+// NotificationBackend 通知后端。Linux 默认使用 freedesktop D-Bus 通知服务，其他平台使用原生库
+//
+// 用户与通知交互时调用 respond：点击通知本身传入空字符串，点击按钮传入按钮 ID。
+type NotificationBackend interface {
+	Show(n Notification, respond func(action string)) error
+}
+
+var (
+	notificationBackend      NotificationBackend
+	notificationBackendMutex sync.RWMutex
+	notificationNextID       uint64
+	notificationIDMutex      sync.Mutex
+)
+
+// SetNotificationBackend 替换通知后端，传入 nil 恢复平台默认实现
+func SetNotificationBackend(backend NotificationBackend) {
+	notificationBackendMutex.Lock()
+	notificationBackend = backend
+	notificationBackendMutex.Unlock()
+}
+
+func currentNotificationBackend() NotificationBackend {
+	notificationBackendMutex.RLock()
+	backend := notificationBackend
+	notificationBackendMutex.RUnlock()
+	if backend == nil {
+		return defaultNotificationBackend()
+	}
+	return backend
+}
+
+// Notify 显示桌面通知，窗口隐藏或全部关闭（配合 SetKeepAlive）时同样可用。
+// OnClick/OnAction 在独立 goroutine 中调用。
+func Notify(n Notification) error {
+	if n.Title == "" {
+		return fmt.Errorf("webview: notification title is required")
+	}
+	if err := validateIcon(n.Icon); err != nil {
+		return err
+	}
+	for _, action := range n.Actions {
+		if action.ID == "" || action.Label == "" {
+			return fmt.Errorf("webview: notification actions need an ID and a label")
+		}
+	}
+	if n.ID == "" {
+		n.ID = nextNotificationID()
+	}
+	onClick, onAction := n.OnClick, n.OnAction
+	return currentNotificationBackend().Show(n, func(action string) {
+		var fn func()
+		switch {
+		case action == "" && onClick != nil:
+			fn = onClick
+		case action != "" && onAction != nil:
+			fn = func() { onAction(action) }
+		default:
+			return
+		}
+		go func() {
+			defer func() {
+				if r := recover(); r != nil {
+					slog.Error("Panic in notification handler", "id", n.ID, "action", action, "panic", r)
+				}
+			}()
+			fn()
+		}()
+	})
+}
+
+func nextNotificationID() string {
+	notificationIDMutex.Lock()
+	defer notificationIDMutex.Unlock()
+	notificationNextID++
+	return "wvapp-" + strconv.FormatUint(notificationNextID, 10)
+}
+
+// NotificationEvent 通过 Webview.Emit 以 "notification" 事件发送给页面
+type NotificationEvent struct {
+	ID     string `json:"id"`
+	Action string `json:"action"` // 空字符串表示点击通知本身
+}
+
+// notifyFromJS 显示 JS 请求的通知，交互结果以 "notification" 事件发回发起调用的窗口
+func notifyFromJS(wv *Webview, args []any) (string, error) {
+	if len(args) == 0 {
+		return "", fmt.Errorf("missing notification options")
+	}
+	data, err := json.Marshal(args[0])
+	if err != nil {
+		return "", err
+	}
+	var opts struct {
+		Notification
+		Icon string `json:"icon"`
+	}
+	if err := json.Unmarshal(data, &opts); err != nil {
+		return "", fmt.Errorf("invalid notification options: %w", err)
+	}
+	n := opts.Notification
+	if opts.Icon != "" {
+		if n.Icon, err = clipboardImageFromJS(opts.Icon); err != nil {
+			return "", err
+		}
+	}
+	if n.ID == "" {
+		n.ID = nextNotificationID()
+	}
+	id := n.ID
+	n.OnClick = func() { _ = wv.Emit("notification", NotificationEvent{ID: id}) }
+	n.OnAction = func(action string) { _ = wv.Emit("notification", NotificationEvent{ID: id, Action: action}) }
+	if err := Notify(n); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// nativeNotificationBackend 通过原生库显示通知（macOS/Windows）
+type nativeNotificationBackend struct{}
+
+var (
+	nativeNotifyCallback     uintptr
+	nativeNotifyCallbackOnce sync.Once
+	nativeNotifyPending      = make(map[uintptr]func(action string))
+	nativeNotifyMutex        sync.Mutex
+	nativeNotifyNextID       uintptr
+)
+
+// cNotificationHandler 原生通知回调，actionPtr 为按钮 ID（点击通知本身时为空字符串），
+// 通知被关闭或过期时原生库不再回调
+func cNotificationHandler(id uintptr, actionPtr uintptr) uintptr {
+	nativeNotifyMutex.Lock()
+	respond := nativeNotifyPending[id]
+	delete(nativeNotifyPending, id)
+	nativeNotifyMutex.Unlock()
+	if respond != nil {
+		respond(goString(actionPtr))
+	}
+	return 0
+}
+
+func (nativeNotificationBackend) Show(n Notification, respond func(action string)) error {
+	if err := loadWebviewLibrary(); err != nil {
+		return err
+	}
+	if webviewNotify == nil {
+		return ErrNotSupported
+	}
+	payload, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	nativeNotifyCallbackOnce.Do(func() { nativeNotifyCallback = purego.NewCallback(cNotificationHandler) })
+
+	nativeNotifyMutex.Lock()
+	nativeNotifyNextID++
+	id := nativeNotifyNextID
+	nativeNotifyPending[id] = respond
+	nativeNotifyMutex.Unlock()
+
+	ok := mainScheduler.RunInMainThreadWithResult(func() any {
+		cstr, ptr := cString(string(payload))
+		ok := webviewNotify(ptr, nativeNotifyCallback, id)
+		runtime.KeepAlive(cstr)
+		return ok
+	}).(bool)
+	if !ok {
+		nativeNotifyMutex.Lock()
+		delete(nativeNotifyPending, id)
+		nativeNotifyMutex.Unlock()
+		return fmt.Errorf("webview: failed to show notification")
+	}
+	return nil
+}
@@ -0,0 +1,185 @@
+package wvapp
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"log/slog"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	dbusNotificationsName  = "org.freedesktop.Notifications"
+	dbusNotificationsPath  = dbus.ObjectPath("/org/freedesktop/Notifications")
+	dbusNotificationsIface = "org.freedesktop.Notifications"
+	dbusDefaultAction      = "default" // 规范中表示点击通知本身的动作
+)
+
+// dbusNotificationBackend 按 freedesktop 桌面通知规范通过会话总线显示通知
+type dbusNotificationBackend struct {
+	mu       sync.Mutex
+	conn     *dbus.Conn
+	pending  map[uint32]func(action string) // D-Bus 通知 ID -> 回调
+	replaces map[string]uint32              // Notification.ID -> D-Bus 通知 ID
+	keys     map[uint32]string              // D-Bus 通知 ID -> Notification.ID，通知关闭时据此清理 replaces
+}
+
+func newDBusNotificationBackend() *dbusNotificationBackend {
+	return &dbusNotificationBackend{
+		pending:  make(map[uint32]func(string)),
+		replaces: make(map[string]uint32),
+		keys:     make(map[uint32]string),
+	}
+}
+
+var (
+	linuxNotificationBackend     *dbusNotificationBackend
+	linuxNotificationBackendOnce sync.Once
+)
+
+func defaultNotificationBackend() NotificationBackend {
+	linuxNotificationBackendOnce.Do(func() {
+		linuxNotificationBackend = newDBusNotificationBackend()
+	})
+	return linuxNotificationBackend
+}
+
+// connectLocked 连接会话总线并订阅通知信号，连接断开后下次调用会重新连接
+func (b *dbusNotificationBackend) connectLocked() (*dbus.Conn, error) {
+	if b.conn != nil && b.conn.Connected() {
+		return b.conn, nil
+	}
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, fmt.Errorf("webview: cannot connect to the session bus: %w", err)
+	}
+	if err := conn.AddMatchSignal(
+		dbus.WithMatchObjectPath(dbusNotificationsPath),
+		dbus.WithMatchInterface(dbusNotificationsIface),
+	); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("webview: cannot subscribe to notification signals: %w", err)
+	}
+	signals := make(chan *dbus.Signal, 16)
+	conn.Signal(signals)
+	go b.handleSignals(signals)
+	b.conn = conn
+	return conn, nil
+}
+
+func (b *dbusNotificationBackend) handleSignals(signals <-chan *dbus.Signal) {
+	for sig := range signals {
+		switch sig.Name {
+		case dbusNotificationsIface + ".ActionInvoked":
+			var id uint32
+			var action string
+			if err := dbus.Store(sig.Body, &id, &action); err != nil {
+				slog.Debug("Ignoring malformed ActionInvoked signal", "error", err)
+				continue
+			}
+			b.mu.Lock()
+			respond := b.pending[id]
+			b.mu.Unlock()
+			if respond == nil {
+				continue
+			}
+			if action == dbusDefaultAction {
+				action = ""
+			}
+			respond(action)
+		case dbusNotificationsIface + ".NotificationClosed":
+			var id, reason uint32
+			if err := dbus.Store(sig.Body, &id, &reason); err != nil {
+				continue
+			}
+			b.mu.Lock()
+			b.releaseLocked(id)
+			b.mu.Unlock()
+		}
+	}
+}
+
+func (b *dbusNotificationBackend) Show(n Notification, respond func(action string)) error {
+	hints := map[string]dbus.Variant{}
+	if len(n.Icon) > 0 {
+		data, err := dbusImageData(n.Icon)
+		if err != nil {
+			return err
+		}
+		hints["image-data"] = dbus.MakeVariant(data)
+	}
+	// 始终提供 default 动作，否则部分通知服务器点击通知时不会发出 ActionInvoked
+	actions := []string{dbusDefaultAction, ""}
+	for _, action := range n.Actions {
+		actions = append(actions, action.ID, action.Label)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	conn, err := b.connectLocked()
+	if err != nil {
+		return err
+	}
+	replaces := b.replaces[n.ID]
+	var id uint32
+	call := conn.Object(dbusNotificationsName, dbusNotificationsPath).Call(
+		dbusNotificationsIface+".Notify", 0,
+		applicationName(), replaces, "", n.Title, n.Body, actions, hints, int32(-1),
+	)
+	if err := call.Store(&id); err != nil {
+		return fmt.Errorf("webview: notification service error: %w", err)
+	}
+	if replaces != 0 && replaces != id {
+		b.releaseLocked(replaces)
+	}
+	b.replaces[n.ID] = id
+	b.keys[id] = n.ID
+	b.pending[id] = respond
+	return nil
+}
+
+// releaseLocked 通知关闭或被替换后释放其回调与 ID 映射。调用方必须持有 b.mu。
+func (b *dbusNotificationBackend) releaseLocked(id uint32) {
+	delete(b.pending, id)
+	if key, ok := b.keys[id]; ok {
+		delete(b.keys, id)
+		if b.replaces[key] == id {
+			delete(b.replaces, key)
+		}
+	}
+}
+
+// dbusImage 对应规范中 image-data 提示的 (iiibiiay) 结构
+type dbusImage struct {
+	Width         int32
+	Height        int32
+	RowStride     int32
+	HasAlpha      bool
+	BitsPerSample int32
+	Channels      int32
+	Data          []byte
+}
+
+// dbusImageData 将 PNG 解码为规范要求的 RGBA 原始数据
+func dbusImageData(pngData []byte) (dbusImage, error) {
+	img, err := png.Decode(bytes.NewReader(pngData))
+	if err != nil {
+		return dbusImage{}, fmt.Errorf("webview: invalid notification icon: %w", err)
+	}
+	bounds := img.Bounds()
+	rgba := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+	return dbusImage{
+		Width:         int32(bounds.Dx()),
+		Height:        int32(bounds.Dy()),
+		RowStride:     int32(rgba.Stride),
+		HasAlpha:      true,
+		BitsPerSample: 8,
+		Channels:      4,
+		Data:          rgba.Pix,
+	}, nil
+}
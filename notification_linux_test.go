@@ -0,0 +1,173 @@
+package wvapp
+
+import (
+	"bufio"
+	"bytes"
+	"image"
+	"image/png"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// startTestBus 启动私有的 dbus-daemon 并将其设为会话总线
+func startTestBus(t *testing.T) {
+	t.Helper()
+	daemon, err := exec.LookPath("dbus-daemon")
+	if err != nil {
+		t.Skip("dbus-daemon not installed")
+	}
+	cmd := exec.Command(daemon, "--session", "--nofork", "--print-address")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot start dbus-daemon: %v", err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+	address, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		t.Fatalf("reading bus address: %v", err)
+	}
+	t.Setenv("DBUS_SESSION_BUS_ADDRESS", strings.TrimSpace(address))
+}
+
+// mockNotificationServer 实现 org.freedesktop.Notifications 的 Notify 方法
+type mockNotificationServer struct {
+	mu     sync.Mutex
+	nextID uint32
+	calls  []mockNotifyCall
+}
+
+type mockNotifyCall struct {
+	appName  string
+	replaces uint32
+	summary  string
+	body     string
+	actions  []string
+	hints    map[string]dbus.Variant
+}
+
+func (s *mockNotificationServer) Notify(appName string, replaces uint32, icon, summary, body string,
+	actions []string, hints map[string]dbus.Variant, timeout int32) (uint32, *dbus.Error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, mockNotifyCall{appName, replaces, summary, body, actions, hints})
+	if replaces != 0 {
+		return replaces, nil
+	}
+	s.nextID++
+	return s.nextID, nil
+}
+
+func TestDBusNotifications(t *testing.T) {
+	startTestBus(t)
+
+	server, err := dbus.ConnectSessionBus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+	mock := &mockNotificationServer{}
+	if err := server.Export(mock, dbusNotificationsPath, dbusNotificationsIface); err != nil {
+		t.Fatal(err)
+	}
+	if reply, err := server.RequestName(dbusNotificationsName, dbus.NameFlagDoNotQueue); err != nil || reply != dbus.RequestNameReplyPrimaryOwner {
+		t.Fatalf("RequestName = %v, %v", reply, err)
+	}
+
+	backend := newDBusNotificationBackend()
+	SetNotificationBackend(backend)
+	defer SetNotificationBackend(nil)
+	defer func() {
+		if backend.conn != nil {
+			backend.conn.Close()
+		}
+	}()
+
+	var icon bytes.Buffer
+	png.Encode(&icon, image.NewNRGBA(image.Rect(0, 0, 2, 3)))
+	events := make(chan string, 4)
+	err = Notify(Notification{
+		ID:       "sync",
+		Title:    "Sync finished",
+		Body:     "All files are up to date",
+		Icon:     icon.Bytes(),
+		Actions:  []NotificationAction{{ID: "open", Label: "Open folder"}},
+		OnClick:  func() { events <- "click" },
+		OnAction: func(id string) { events <- "action:" + id },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mock.mu.Lock()
+	call := mock.calls[0]
+	mock.mu.Unlock()
+	if call.summary != "Sync finished" || call.body != "All files are up to date" || call.appName == "" {
+		t.Fatalf("Notify call = %+v", call)
+	}
+	if strings.Join(call.actions, ",") != "default,,open,Open folder" {
+		t.Fatalf("actions = %q", call.actions)
+	}
+	var img dbusImage
+	if err := call.hints["image-data"].Store(&img); err != nil || img.Width != 2 || img.Height != 3 || len(img.Data) != 2*3*4 {
+		t.Fatalf("image-data hint = %+v, %v", img, err)
+	}
+
+	emit := func(member string, values ...any) {
+		if err := server.Emit(dbusNotificationsPath, dbusNotificationsIface+"."+member, values...); err != nil {
+			t.Fatal(err)
+		}
+	}
+	emit("ActionInvoked", uint32(1), "open")
+	emit("ActionInvoked", uint32(1), "default")
+	got := map[string]bool{}
+	for range 2 {
+		select {
+		case ev := <-events:
+			got[ev] = true
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for notification callbacks")
+		}
+	}
+	if !got["action:open"] || !got["click"] {
+		t.Fatalf("events = %v", got)
+	}
+
+	// 同一 ID 再次通知时替换原通知
+	if err := Notify(Notification{ID: "sync", Title: "Sync started"}); err != nil {
+		t.Fatal(err)
+	}
+	mock.mu.Lock()
+	replaces := mock.calls[1].replaces
+	mock.mu.Unlock()
+	if replaces != 1 {
+		t.Fatalf("second notification replaces %d, want 1", replaces)
+	}
+
+	emit("NotificationClosed", uint32(1), uint32(2))
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		backend.mu.Lock()
+		_, open := backend.pending[1]
+		// 关闭后不再保留 Notification.ID 的映射，长期运行的应用不会无限增长
+		released := !open && len(backend.replaces) == 0 && len(backend.keys) == 0
+		backend.mu.Unlock()
+		if released {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("closed notification was not released")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
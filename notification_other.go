@@ -0,0 +1,7 @@
+//go:build !linux
+
+package wvapp
+
+func defaultNotificationBackend() NotificationBackend {
+	return nativeNotificationBackend{}
+}
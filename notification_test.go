@@ -0,0 +1,97 @@
+package wvapp
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// recordingNotifier 记录显示的通知，并保存 respond 供测试模拟用户操作
+type recordingNotifier struct {
+	shown    []Notification
+	responds []func(action string)
+}
+
+func (r *recordingNotifier) Show(n Notification, respond func(action string)) error {
+	r.shown = append(r.shown, n)
+	r.responds = append(r.responds, respond)
+	return nil
+}
+
+func TestNotify(t *testing.T) {
+	backend := &recordingNotifier{}
+	SetNotificationBackend(backend)
+	defer SetNotificationBackend(nil)
+
+	events := make(chan string, 2)
+	err := Notify(Notification{
+		Title:    "Sync finished",
+		Body:     "42 files uploaded",
+		Actions:  []NotificationAction{{ID: "open", Label: "Open folder"}},
+		OnClick:  func() { events <- "click" },
+		OnAction: func(id string) { events <- "action:" + id },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backend.shown) != 1 || backend.shown[0].ID == "" || backend.shown[0].Body != "42 files uploaded" {
+		t.Fatalf("shown = %+v", backend.shown)
+	}
+
+	backend.responds[0]("")
+	backend.responds[0]("open")
+	got := map[string]bool{}
+	for range 2 {
+		select {
+		case ev := <-events:
+			got[ev] = true
+		case <-time.After(time.Second):
+			t.Fatal("notification callback not called")
+		}
+	}
+	if !got["click"] || !got["action:open"] {
+		t.Fatalf("events = %v", got)
+	}
+}
+
+func TestNotifyValidation(t *testing.T) {
+	SetNotificationBackend(&recordingNotifier{})
+	defer SetNotificationBackend(nil)
+
+	for name, n := range map[string]Notification{
+		"no title":     {Body: "body"},
+		"bad icon":     {Title: "t", Icon: []byte("GIF89a")},
+		"empty action": {Title: "t", Actions: []NotificationAction{{ID: "x"}}},
+	} {
+		if err := Notify(n); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestNotifyFromJS(t *testing.T) {
+	backend := &recordingNotifier{}
+	SetNotificationBackend(backend)
+	defer SetNotificationBackend(nil)
+
+	png := append([]byte("\x89PNG\r\n\x1a\n"), 0)
+	args := []any{map[string]any{
+		"id":      "job-7",
+		"title":   "Done",
+		"icon":    clipboardImageDataURL(png),
+		"actions": []any{map[string]any{"id": "retry", "label": "Retry"}},
+	}}
+	id, err := UserFunctionRegistry["_go_runtime_notify"](context.Background(), fakeWebview(), args)
+	if err != nil || id != "job-7" {
+		t.Fatalf("notify from JS = %v, %v", id, err)
+	}
+	n := backend.shown[0]
+	if n.Title != "Done" || len(n.Icon) != len(png) || n.Actions[0].Label != "Retry" || n.OnClick == nil || n.OnAction == nil {
+		t.Fatalf("notification from JS = %+v", n)
+	}
+
+	id, err = UserFunctionRegistry["_go_runtime_notify"](context.Background(), fakeWebview(), []any{map[string]any{"title": "No ID"}})
+	if err != nil || id == "" {
+		t.Fatalf("notify without ID = %v, %v", id, err)
+	}
+}
@@ -0,0 +1,260 @@
+package wvapp
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/ebitengine/purego"
+)
+
+// PermissionKind 网页请求的权限类型，名称与浏览器 Permissions API 一致
+type PermissionKind string
+
+const (
+	PermissionCamera        PermissionKind = "camera"
+	PermissionMicrophone    PermissionKind = "microphone"
+	PermissionGeolocation   PermissionKind = "geolocation"
+	PermissionNotifications PermissionKind = "notifications"
+	PermissionClipboardRead PermissionKind = "clipboard-read"
+	PermissionDisplay       PermissionKind = "display-capture" // 屏幕共享
+	PermissionMIDI          PermissionKind = "midi"
+)
+
+// PermissionDecision 权限决定
+type PermissionDecision int32
+
+const (
+	// PermissionPrompt 尚未决定。由 PermissionHandler 返回时表示稍后通过 SetPermission 异步回答
+	PermissionPrompt PermissionDecision = iota
+	PermissionGranted
+	PermissionDenied
+)
+
+func (d PermissionDecision) String() string {
+	switch d {
+	case PermissionGranted:
+		return "granted"
+	case PermissionDenied:
+		return "denied"
+	}
+	return "prompt"
+}
+
+// PermissionHandler 权限请求回调，在主线程中同步调用，不应执行耗时操作。
+// 返回的 PermissionGranted/PermissionDenied 对该来源与权限类型记住，之后的请求不再调用回调
+type PermissionHandler func(origin string, kind PermissionKind) PermissionDecision
+
+type permissionKey struct {
+	origin string
+	kind   PermissionKind
+}
+
+// pendingPermission 等待异步回答的原生请求
+type pendingPermission struct {
+	w  *Webview
+	id uintptr
+}
+
+var (
+	permissionMutex        sync.Mutex
+	permissionDecisions    = make(map[permissionKey]PermissionDecision)
+	permissionHandlers     = make(map[*Webview]PermissionHandler)
+	permissionPending      = make(map[permissionKey][]pendingPermission)
+	permissionCallback     uintptr
+	permissionCallbackOnce sync.Once
+
+	// respondPermission 回答等待中的原生请求，测试中替换
+	respondPermission = func(w *Webview, id uintptr, granted bool) {
+		mainScheduler.RunInMainThread(func() { webviewPermissionRespond(w, id, granted) })
+	}
+)
+
+// permissionOrigin 将 URL 或来源转换为 scheme://host[:port] 形式，本地文件统一为 file://
+func permissionOrigin(raw string) (string, error) {
+	u, err := url.Parse(raw)
+	if err == nil && strings.EqualFold(u.Scheme, "file") {
+		return "file://", nil
+	}
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("webview: invalid origin %q", raw)
+	}
+	scheme := strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Hostname())
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	port := u.Port()
+	if (scheme == "http" && port == "80") || (scheme == "https" && port == "443") {
+		port = ""
+	}
+	if port != "" {
+		host += ":" + port
+	}
+	return scheme + "://" + host, nil
+}
+
+// OnPermissionRequest 设置窗口的权限请求回调，nil 表示拒绝所有未记住决定的请求
+func (w *Webview) OnPermissionRequest(handler PermissionHandler) {
+	permissionMutex.Lock()
+	defer permissionMutex.Unlock()
+	if handler == nil {
+		delete(permissionHandlers, w)
+		return
+	}
+	permissionHandlers[w] = handler
+}
+
+// SetPermission 记住来源的权限决定，并回答该来源所有等待中的同类请求；
+// 传入 PermissionPrompt 忘记已记住的决定，下次请求时重新调用回调
+func SetPermission(origin string, kind PermissionKind, decision PermissionDecision) error {
+	origin, err := permissionOrigin(origin)
+	if err != nil {
+		return err
+	}
+	key := permissionKey{origin: origin, kind: kind}
+	permissionMutex.Lock()
+	if decision == PermissionPrompt {
+		delete(permissionDecisions, key)
+		permissionMutex.Unlock()
+		return nil
+	}
+	permissionDecisions[key] = decision
+	pending := permissionPending[key]
+	delete(permissionPending, key)
+	permissionMutex.Unlock()
+
+	for _, p := range pending {
+		respondPermission(p.w, p.id, decision == PermissionGranted)
+	}
+	return nil
+}
+
+// Permission 返回来源已记住的决定，没有时为 PermissionPrompt
+func Permission(origin string, kind PermissionKind) PermissionDecision {
+	origin, err := permissionOrigin(origin)
+	if err != nil {
+		return PermissionPrompt
+	}
+	permissionMutex.Lock()
+	defer permissionMutex.Unlock()
+	return permissionDecisions[permissionKey{origin: origin, kind: kind}]
+}
+
+// ResetPermissions 忘记来源的全部决定，origin 为空时忘记所有来源的决定
+func ResetPermissions(origin string) error {
+	if origin != "" {
+		var err error
+		if origin, err = permissionOrigin(origin); err != nil {
+			return err
+		}
+	}
+	permissionMutex.Lock()
+	defer permissionMutex.Unlock()
+	for key := range permissionDecisions {
+		if origin == "" || key.origin == origin {
+			delete(permissionDecisions, key)
+		}
+	}
+	return nil
+}
+
+// cPermissionHandler 原生权限请求回调：返回 1 允许、2 拒绝；
+// 返回 0 表示稍后通过 webview_permission_respond 回答
+func cPermissionHandler(wv *Webview, id uintptr, originPtr uintptr, kindPtr uintptr) uintptr {
+	return uintptr(wv.decidePermission(id, goString(originPtr), PermissionKind(goString(kindPtr))))
+}
+
+// decidePermission 依次使用已记住的决定、窗口回调；回调返回 PermissionPrompt 时登记为等待中
+func (w *Webview) decidePermission(id uintptr, rawOrigin string, kind PermissionKind) PermissionDecision {
+	origin, err := permissionOrigin(rawOrigin)
+	if err != nil {
+		slog.Warn("Denying permission request", "kind", kind, "error", err)
+		return PermissionDenied
+	}
+	key := permissionKey{origin: origin, kind: kind}
+	permissionMutex.Lock()
+	decision, ok := permissionDecisions[key]
+	handler := permissionHandlers[w]
+	permissionMutex.Unlock()
+	if ok {
+		return decision
+	}
+	if handler == nil {
+		return PermissionDenied
+	}
+
+	panicked := false
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				slog.Error("Panic in permission handler", "origin", origin, "kind", kind, "panic", r)
+				panicked = true
+			}
+		}()
+		decision = handler(origin, kind)
+	}()
+	if panicked {
+		return PermissionDenied // 只拒绝本次请求，不记住
+	}
+
+	permissionMutex.Lock()
+	defer permissionMutex.Unlock()
+	if decision != PermissionPrompt {
+		permissionDecisions[key] = decision
+		return decision
+	}
+	// 回调执行期间可能已经通过 SetPermission 作出决定
+	if remembered, ok := permissionDecisions[key]; ok {
+		return remembered
+	}
+	permissionPending[key] = append(permissionPending[key], pendingPermission{w: w, id: id})
+	return PermissionPrompt
+}
+
+// installPermissions 安装权限请求回调，原生库不支持时由 WebView 自行决定
+func (w *Webview) installPermissions() {
+	if webviewSetPermissionCallback == nil || webviewPermissionRespond == nil {
+		return
+	}
+	permissionCallbackOnce.Do(func() { permissionCallback = purego.NewCallback(cPermissionHandler) })
+	mainScheduler.RunInMainThread(func() { webviewSetPermissionCallback(w, permissionCallback) })
+}
+
+// releasePermissions 窗口关闭时丢弃回调与等待中的请求，已记住的决定保留
+func releasePermissions(wv *Webview) {
+	permissionMutex.Lock()
+	defer permissionMutex.Unlock()
+	delete(permissionHandlers, wv)
+	for key, list := range permissionPending {
+		kept := list[:0]
+		for _, p := range list {
+			if p.w != wv {
+				kept = append(kept, p)
+			}
+		}
+		if len(kept) == 0 {
+			delete(permissionPending, key)
+		} else {
+			permissionPending[key] = kept
+		}
+	}
+}
+
+// queryPermissionFromJS 返回页面当前来源的权限状态，来源取自窗口 URL 而不是页面参数
+func queryPermissionFromJS(wv *Webview, args []any) (string, error) {
+	if len(args) < 1 {
+		return "", fmt.Errorf("missing permission kind")
+	}
+	kind, ok := args[0].(string)
+	if !ok || kind == "" {
+		return "", fmt.Errorf("invalid permission kind")
+	}
+	state, err := wv.State()
+	if err != nil {
+		return "", err
+	}
+	return Permission(state.URL, PermissionKind(kind)).String(), nil
+}
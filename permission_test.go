@@ -0,0 +1,129 @@
+package wvapp
+
+import (
+	"testing"
+)
+
+func TestPermissionOrigin(t *testing.T) {
+	for in, want := range map[string]string{
+		"https://Example.com/path?q=1": "https://example.com",
+		"https://example.com:443/":     "https://example.com",
+		"http://localhost:8080/app":    "http://localhost:8080",
+		"wvapp://app/index.html":       "wvapp://app",
+		"http://[::1]:3000/":           "http://[::1]:3000",
+		"file:///home/user/index.html": "file://",
+	} {
+		got, err := permissionOrigin(in)
+		if err != nil || got != want {
+			t.Errorf("permissionOrigin(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "about:blank", "example.com"} {
+		if _, err := permissionOrigin(in); err == nil {
+			t.Errorf("permissionOrigin(%q): expected an error", in)
+		}
+	}
+}
+
+func TestDecidePermissionRemembers(t *testing.T) {
+	wv := fakeWebview()
+	defer releasePermissions(wv)
+	defer ResetPermissions("")
+
+	if got := wv.decidePermission(1, "https://meet.example.com/room", PermissionCamera); got != PermissionDenied {
+		t.Fatalf("without handler = %v, want denied", got)
+	}
+	if got := Permission("https://meet.example.com", PermissionCamera); got != PermissionPrompt {
+		t.Fatalf("default deny was remembered: %v", got)
+	}
+
+	calls := 0
+	wv.OnPermissionRequest(func(origin string, kind PermissionKind) PermissionDecision {
+		calls++
+		if origin != "https://meet.example.com" {
+			t.Errorf("origin = %q", origin)
+		}
+		if kind == PermissionCamera {
+			return PermissionGranted
+		}
+		return PermissionDenied
+	})
+	for i := 0; i < 2; i++ {
+		if got := wv.decidePermission(2, "https://meet.example.com/other", PermissionCamera); got != PermissionGranted {
+			t.Fatalf("camera = %v, want granted", got)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("handler called %d times, want 1", calls)
+	}
+	if got := wv.decidePermission(3, "https://meet.example.com/", PermissionGeolocation); got != PermissionDenied {
+		t.Fatalf("geolocation = %v, want denied", got)
+	}
+	if Permission("https://meet.example.com", PermissionGeolocation) != PermissionDenied {
+		t.Fatal("denial was not remembered")
+	}
+
+	if err := ResetPermissions("https://meet.example.com"); err != nil {
+		t.Fatal(err)
+	}
+	if Permission("https://meet.example.com", PermissionCamera) != PermissionPrompt {
+		t.Fatal("ResetPermissions kept the decision")
+	}
+}
+
+func TestDecidePermissionAsync(t *testing.T) {
+	type answer struct {
+		w       *Webview
+		id      uintptr
+		granted bool
+	}
+	var answers []answer
+	saved := respondPermission
+	respondPermission = func(w *Webview, id uintptr, granted bool) { answers = append(answers, answer{w, id, granted}) }
+	defer func() { respondPermission = saved }()
+
+	first, second := fakeWebview(), fakeWebview()
+	defer releasePermissions(first)
+	defer releasePermissions(second)
+	defer ResetPermissions("")
+	ask := func(string, PermissionKind) PermissionDecision { return PermissionPrompt }
+	first.OnPermissionRequest(ask)
+	second.OnPermissionRequest(ask)
+
+	if got := first.decidePermission(10, "https://chat.example.com", PermissionMicrophone); got != PermissionPrompt {
+		t.Fatalf("first = %v, want prompt", got)
+	}
+	if got := second.decidePermission(11, "https://chat.example.com/b", PermissionMicrophone); got != PermissionPrompt {
+		t.Fatalf("second = %v, want prompt", got)
+	}
+	releasePermissions(second)
+
+	if err := SetPermission("https://chat.example.com", PermissionMicrophone, PermissionGranted); err != nil {
+		t.Fatal(err)
+	}
+	if len(answers) != 1 || answers[0] != (answer{first, 10, true}) {
+		t.Fatalf("answers = %+v", answers)
+	}
+	if got := first.decidePermission(12, "https://chat.example.com", PermissionMicrophone); got != PermissionGranted {
+		t.Fatalf("after SetPermission = %v, want granted", got)
+	}
+
+	if err := SetPermission("https://chat.example.com", PermissionMicrophone, PermissionPrompt); err != nil {
+		t.Fatal(err)
+	}
+	if Permission("https://chat.example.com", PermissionMicrophone) != PermissionPrompt {
+		t.Fatal("PermissionPrompt did not forget the decision")
+	}
+}
+
+func TestDecidePermissionHandlerPanics(t *testing.T) {
+	wv := fakeWebview()
+	defer releasePermissions(wv)
+	wv.OnPermissionRequest(func(string, PermissionKind) PermissionDecision { panic("boom") })
+	if got := wv.decidePermission(1, "https://example.com", PermissionNotifications); got != PermissionDenied {
+		t.Fatalf("decision = %v, want denied", got)
+	}
+	if Permission("https://example.com", PermissionNotifications) != PermissionPrompt {
+		t.Fatal("denial after a panic was remembered")
+	}
+}
@@ -0,0 +1,224 @@
+package wvapp
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// saveAsPDFRenderTimeout SaveAsPDF 中导出 PDF 步骤的时限。JS 端的 SaveAsPDF 不受工作池时限约束
+// （用户可以在保存对话框中停留任意长时间，窗口关闭时才放弃），导出步骤由这个时限兜底
+var saveAsPDFRenderTimeout = 30 * time.Second
+
+// PageSize 纸张尺寸，单位为毫米（纵向）
+type PageSize struct {
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// 常用纸张尺寸
+var (
+	PageA3     = PageSize{Width: 297, Height: 420}
+	PageA4     = PageSize{Width: 210, Height: 297}
+	PageA5     = PageSize{Width: 148, Height: 210}
+	PageLetter = PageSize{Width: 215.9, Height: 279.4}
+	PageLegal  = PageSize{Width: 215.9, Height: 355.6}
+)
+
+// Margins 页边距，单位为毫米
+type Margins struct {
+	Top    float64 `json:"top"`
+	Right  float64 `json:"right"`
+	Bottom float64 `json:"bottom"`
+	Left   float64 `json:"left"`
+}
+
+// defaultMargins 未设置 Margins 时使用的边距
+var defaultMargins = Margins{Top: 10, Right: 10, Bottom: 10, Left: 10}
+
+// PrintOptions 打印与导出 PDF 的选项
+type PrintOptions struct {
+	PageSize        PageSize `json:"pageSize"`          // 零值：打印时使用打印机默认纸张，导出 PDF 时为 A4
+	Margins         *Margins `json:"margins,omitempty"` // nil 表示四边各 10 毫米
+	Landscape       bool     `json:"landscape"`
+	PrintBackground bool     `json:"printBackground"` // 打印背景颜色与图片
+	Scale           float64  `json:"scale"`           // 缩放比例 0.1-2，0 表示 1
+}
+
+// normalizePrintOptions 校验选项并填充默认值，pdf 为 true 时纸张尺寸不能留给打印机决定
+func normalizePrintOptions(opts PrintOptions, pdf bool) (PrintOptions, error) {
+	size := opts.PageSize
+	switch {
+	case size == PageSize{}:
+		if pdf {
+			opts.PageSize = PageA4
+		}
+	case size.Width <= 0 || size.Height <= 0:
+		return opts, fmt.Errorf("webview: invalid page size %gx%g mm", size.Width, size.Height)
+	}
+	if opts.Margins == nil {
+		m := defaultMargins
+		opts.Margins = &m
+	}
+	m := *opts.Margins
+	if m.Top < 0 || m.Right < 0 || m.Bottom < 0 || m.Left < 0 {
+		return opts, fmt.Errorf("webview: page margins cannot be negative")
+	}
+	if opts.PageSize != (PageSize{}) {
+		width, height := opts.PageSize.Width, opts.PageSize.Height
+		if opts.Landscape {
+			width, height = height, width
+		}
+		if m.Left+m.Right >= width || m.Top+m.Bottom >= height {
+			return opts, fmt.Errorf("webview: page margins leave no printable area")
+		}
+	}
+	if opts.Scale == 0 {
+		opts.Scale = 1
+	}
+	if opts.Scale < 0.1 || opts.Scale > 2 {
+		return opts, fmt.Errorf("webview: print scale %g is outside 0.1-2", opts.Scale)
+	}
+	return opts, nil
+}
+
+// Print 显示系统打印对话框，选项作为对话框的初始设置；对话框打开后立即返回
+func (w *Webview) Print(opts PrintOptions) error {
+	opts, err := normalizePrintOptions(opts, false)
+	if err != nil {
+		return err
+	}
+	if webviewPrint == nil {
+		return ErrNotSupported
+	}
+	data, err := json.Marshal(opts)
+	if err != nil {
+		return err
+	}
+	mainScheduler.RunInMainThread(func() {
+		cstr, ptr := cString(string(data))
+		webviewPrint(w, ptr)
+		runtime.KeepAlive(cstr)
+	})
+	return nil
+}
+
+// PrintToPDF 将当前页面按打印样式（@media print）渲染为 PDF，不显示任何界面，
+// 因此也适用于 Headless 窗口
+func (w *Webview) PrintToPDF(ctx context.Context, opts PrintOptions) ([]byte, error) {
+	opts, err := normalizePrintOptions(opts, true)
+	if err != nil {
+		return nil, err
+	}
+	if webviewPrintToPDF == nil {
+		return nil, ErrNotSupported
+	}
+	data, err := json.Marshal(opts)
+	if err != nil {
+		return nil, err
+	}
+	pdf, err := requestBytes(ctx, func(callback, id uintptr) {
+		cstr, ptr := cString(string(data))
+		webviewPrintToPDF(w, ptr, callback, id)
+		runtime.KeepAlive(cstr)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.HasPrefix(pdf, []byte("%PDF-")) {
+		return nil, fmt.Errorf("webview: native library returned invalid PDF data")
+	}
+	return pdf, nil
+}
+
+// printOptionsFromJS 将 JS 传入的选项对象转换为 PrintOptions，pageSize 可以是 "A4" 等名称
+func printOptionsFromJS(args []any) (PrintOptions, error) {
+	var opts PrintOptions
+	if len(args) == 0 || args[0] == nil {
+		return opts, nil
+	}
+	obj, ok := args[0].(map[string]any)
+	if !ok {
+		return opts, fmt.Errorf("invalid print options")
+	}
+	if name, ok := obj["pageSize"].(string); ok {
+		size, found := pageSizeByName(name)
+		if !found {
+			return opts, fmt.Errorf("unknown page size %q", name)
+		}
+		obj = maps.Clone(obj)
+		obj["pageSize"] = size
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return opts, err
+	}
+	if err := json.Unmarshal(data, &opts); err != nil {
+		return opts, fmt.Errorf("invalid print options: %w", err)
+	}
+	return opts, nil
+}
+
+func pageSizeByName(name string) (PageSize, bool) {
+	switch strings.ToLower(name) {
+	case "a3":
+		return PageA3, true
+	case "a4":
+		return PageA4, true
+	case "a5":
+		return PageA5, true
+	case "letter":
+		return PageLetter, true
+	case "legal":
+		return PageLegal, true
+	}
+	return PageSize{}, false
+}
+
+// pdfDataURL JS 中以 data URL 表示 PDF
+func pdfDataURL(pdf []byte) string {
+	return "data:application/pdf;base64," + base64.StdEncoding.EncodeToString(pdf)
+}
+
+// saveAsPDFFromJS 先在 saveAsPDFRenderTimeout 内导出 PDF，再让用户选择保存位置，返回保存路径（取消时为空）；
+// ctx 应当不带时限，由调用方在窗口关闭时取消
+func saveAsPDFFromJS(ctx context.Context, wv *Webview, args []any) (string, error) {
+	opts, err := printOptionsFromJS(args)
+	if err != nil {
+		return "", err
+	}
+	name := "document.pdf"
+	if len(args) > 1 {
+		if s, ok := args[1].(string); ok && s != "" {
+			name = sanitizeDownloadName(s)
+			if !strings.HasSuffix(strings.ToLower(name), ".pdf") {
+				name += ".pdf"
+			}
+		}
+	}
+	renderCtx, cancel := context.WithTimeout(ctx, saveAsPDFRenderTimeout)
+	pdf, err := wv.PrintToPDF(renderCtx, opts)
+	cancel()
+	if err != nil {
+		return "", err
+	}
+	path, err := SaveFileDialog(ctx, FileDialogOptions{
+		DefaultPath: name,
+		Filters:     []FileFilter{{Name: "PDF", Patterns: []string{"*.pdf"}}},
+		Parent:      wv,
+	})
+	if err != nil || path == "" {
+		return "", err
+	}
+	if err := os.WriteFile(path, pdf, 0o644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,141 @@
+package wvapp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNormalizePrintOptions(t *testing.T) {
+	opts, err := normalizePrintOptions(PrintOptions{}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.PageSize != PageA4 || *opts.Margins != defaultMargins || opts.Scale != 1 {
+		t.Fatalf("PDF defaults = %+v, margins %+v", opts, *opts.Margins)
+	}
+	if opts, _ := normalizePrintOptions(PrintOptions{}, false); opts.PageSize != (PageSize{}) {
+		t.Fatalf("print dialog should keep the printer's paper, got %+v", opts.PageSize)
+	}
+
+	noMargins, err := normalizePrintOptions(PrintOptions{PageSize: PageLetter, Margins: &Margins{}}, true)
+	if err != nil || *noMargins.Margins != (Margins{}) {
+		t.Fatalf("zero margins = %+v, %v", noMargins.Margins, err)
+	}
+
+	for name, o := range map[string]PrintOptions{
+		"negative size":   {PageSize: PageSize{Width: -1, Height: 100}},
+		"half size":       {PageSize: PageSize{Width: 100}},
+		"negative margin": {Margins: &Margins{Top: -1}},
+		"no area":         {PageSize: PageA5, Margins: &Margins{Left: 80, Right: 80}},
+		"no area rotated": {PageSize: PageA5, Landscape: true, Margins: &Margins{Top: 80, Bottom: 80}},
+		"scale too small": {Scale: 0.05},
+		"scale too large": {Scale: 3},
+	} {
+		if _, err := normalizePrintOptions(o, true); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestPrintOptionsFromJS(t *testing.T) {
+	opts, err := printOptionsFromJS([]any{map[string]any{
+		"pageSize":        "letter",
+		"landscape":       true,
+		"printBackground": true,
+		"margins":         map[string]any{"top": float64(5), "right": float64(5), "bottom": float64(5), "left": float64(5)},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.PageSize != PageLetter || !opts.Landscape || !opts.PrintBackground || opts.Margins.Top != 5 {
+		t.Fatalf("opts = %+v", opts)
+	}
+	custom, err := printOptionsFromJS([]any{map[string]any{"pageSize": map[string]any{"width": float64(100), "height": float64(150)}}})
+	if err != nil || custom.PageSize != (PageSize{Width: 100, Height: 150}) {
+		t.Fatalf("custom size = %+v, %v", custom.PageSize, err)
+	}
+	if _, err := printOptionsFromJS([]any{map[string]any{"pageSize": "B5"}}); err == nil {
+		t.Fatal("expected an error for an unknown page size")
+	}
+	if opts, err := printOptionsFromJS(nil); err != nil || opts.Margins != nil {
+		t.Fatalf("no options = %+v, %v", opts, err)
+	}
+}
+
+// callSaveAsPDF 在 goroutine 中经由 JS 入口调用 SaveAsPDF，同时在当前 goroutine 中运行主线程任务
+func callSaveAsPDF(t *testing.T, ctx context.Context, wv *Webview) (any, error) {
+	t.Helper()
+	type result struct {
+		value any
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		value, err := UserFunctionRegistry["_go_runtime_saveAsPDF"](ctx, wv, nil)
+		done <- result{value, err}
+	}()
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case r := <-done:
+			return r.value, r.err
+		case <-timeout:
+			t.Fatal("SaveAsPDF did not return")
+		default:
+			mainScheduler.PollTasks()
+			time.Sleep(time.Millisecond)
+		}
+	}
+}
+
+func TestSaveAsPDFRenderDeadline(t *testing.T) {
+	original, timeout := webviewPrintToPDF, saveAsPDFRenderTimeout
+	defer func() {
+		mainScheduler.PollTasks()
+		webviewPrintToPDF, saveAsPDFRenderTimeout = original, timeout
+	}()
+	// 原生端始终不回调
+	webviewPrintToPDF = func(*Webview, uintptr, uintptr, uintptr) {}
+	saveAsPDFRenderTimeout = 10 * time.Millisecond
+
+	wv := fakeWebview()
+	defer releaseWindow(wv)
+	// JS 入口不受工作池时限约束，导出步骤仍需按自己的时限结束
+	_, err := callSaveAsPDF(t, context.Background(), wv)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected render deadline, got %v", err)
+	}
+}
+
+func TestSaveAsPDFOutlivesWorkerDeadline(t *testing.T) {
+	original, timeout := webviewPrintToPDF, saveAsPDFRenderTimeout
+	defer func() {
+		mainScheduler.PollTasks()
+		webviewPrintToPDF, saveAsPDFRenderTimeout = original, timeout
+	}()
+	// 原生端在工作池时限之后才送回 PDF
+	webviewPrintToPDF = func(_ *Webview, _ uintptr, _ uintptr, id uintptr) {
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			bytesRequests.resolve(id, bytesResult{data: []byte("%PDF-1.7")})
+		}()
+	}
+	saveAsPDFRenderTimeout = time.Second
+	backend := &scriptedDialogBackend{save: []string{""}}
+	SetDialogBackend(backend)
+	defer SetDialogBackend(nil)
+
+	wv := fakeWebview()
+	defer releaseWindow(wv)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	result, err := callSaveAsPDF(t, ctx, wv)
+	if err != nil || result != nil {
+		t.Fatalf("canceled save should resolve to null, got %v, %v", result, err)
+	}
+	if len(backend.calls) != 1 || backend.calls[0].DefaultPath != "document.pdf" || backend.calls[0].Parent != wv {
+		t.Fatalf("save dialog options = %+v", backend.calls)
+	}
+}
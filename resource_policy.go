@@ -0,0 +1,146 @@
+package wvapp
+
+import (
+	"errors"
+	"io/fs"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// DefaultMaxResourcePathLength 默认允许的最大资源路径长度（字节）
+const DefaultMaxResourcePathLength = 1024
+
+var (
+	ErrInvalidResourcePath = errors.New("invalid resource path")
+	ErrResourceForbidden   = errors.New("resource access forbidden")
+)
+
+// ResourcePolicy 资源访问策略
+//
+// 路径统一使用 "/" 分隔且相对于资源根目录（例如 "assets/app.js"）。
+// 模式语法同 path.Match，另外支持 "**" 匹配任意层目录；
+// 不含 "/" 的模式匹配文件名（例如 "*.map" 匹配任意目录下的 source map），
+// 以 "/" 开头的模式从根目录开始匹配（例如 "/index.html" 仅匹配根目录下的文件）。
+type ResourcePolicy struct {
+	Allow         []string // 允许访问的模式（为空表示允许全部）
+	Deny          []string // 拒绝访问的模式，优先级高于 Allow
+	AllowHidden   bool     // 是否允许访问以 "." 开头的文件或目录
+	MaxPathLength int      // 最大路径长度（0表示使用 DefaultMaxResourcePathLength）
+}
+
+// DefaultResourcePolicy 默认策略：隐藏文件与 source map 不对外提供
+func DefaultResourcePolicy() ResourcePolicy {
+	return ResourcePolicy{
+		Deny: []string{"*.map"},
+	}
+}
+
+// CleanResourcePath 将 URI 请求路径规范化为 fs.FS 可用的相对路径
+//
+// 拒绝 NUL 字节、反斜杠、编码后的路径分隔符、超长路径以及任何越过根目录的路径。
+func CleanResourcePath(raw string, maxLen int) (string, error) {
+	if maxLen <= 0 {
+		maxLen = DefaultMaxResourcePathLength
+	}
+	if len(raw) > maxLen {
+		return "", ErrInvalidResourcePath
+	}
+	if i := strings.IndexAny(raw, "?#"); i >= 0 {
+		raw = raw[:i]
+	}
+	lower := strings.ToLower(raw)
+	if strings.Contains(lower, "%2f") || strings.Contains(lower, "%5c") || strings.Contains(lower, "%00") {
+		return "", ErrInvalidResourcePath
+	}
+	p, err := url.PathUnescape(raw)
+	if err != nil {
+		return "", ErrInvalidResourcePath
+	}
+	if strings.ContainsAny(p, "\x00\\") {
+		return "", ErrInvalidResourcePath
+	}
+	for _, seg := range strings.Split(p, "/") {
+		if seg == ".." {
+			return "", ErrInvalidResourcePath
+		}
+	}
+
+	dir := strings.HasSuffix(p, "/")
+	p = path.Clean("/" + p)
+	if dir && p != "/" {
+		p += "/"
+	}
+	p = normalizePath(p)
+	if !fs.ValidPath(p) {
+		return "", ErrInvalidResourcePath
+	}
+	return p, nil
+}
+
+// Resolve 校验并规范化请求路径，返回可直接用于 fs.FS 的路径
+func (p ResourcePolicy) Resolve(raw string) (string, error) {
+	name, err := CleanResourcePath(raw, p.MaxPathLength)
+	if err != nil {
+		return "", err
+	}
+	if !p.Allowed(name) {
+		return "", ErrResourceForbidden
+	}
+	return name, nil
+}
+
+// Allowed 判断规范化后的路径是否允许访问
+func (p ResourcePolicy) Allowed(name string) bool {
+	if !p.AllowHidden {
+		for _, seg := range strings.Split(name, "/") {
+			if strings.HasPrefix(seg, ".") {
+				return false
+			}
+		}
+	}
+	for _, pattern := range p.Deny {
+		if matchResourcePattern(pattern, name) {
+			return false
+		}
+	}
+	if len(p.Allow) == 0 {
+		return true
+	}
+	for _, pattern := range p.Allow {
+		if matchResourcePattern(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchResourcePattern(pattern, name string) bool {
+	if !strings.Contains(pattern, "/") {
+		ok, _ := path.Match(pattern, path.Base(name))
+		return ok
+	}
+	pattern = strings.TrimPrefix(pattern, "/")
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
@@ -55,7 +55,7 @@ func init() {
 		for _, arg := range args {
 			logParts = append(logParts, fmt.Sprintf("%v", arg))
 		}
-		slog.Info("[JS Console]", "message", strings.Join(logParts, " "))
+		slog.Info("[JS Console]", "window", wv.ID(), "message", strings.Join(logParts, " "))
 		return nil, nil
 	}
 
@@ -64,7 +64,7 @@ func init() {
 		for _, arg := range args {
 			logParts = append(logParts, fmt.Sprintf("%v", arg))
 		}
-		slog.Warn("[JS Console]", "message", strings.Join(logParts, " "))
+		slog.Warn("[JS Console]", "window", wv.ID(), "message", strings.Join(logParts, " "))
 		return nil, nil
 	}
 
@@ -73,7 +73,7 @@ func init() {
 		for _, arg := range args {
 			logParts = append(logParts, fmt.Sprintf("%v", arg))
 		}
-		slog.Error("[JS Console]", "message", strings.Join(logParts, " "))
+		slog.Error("[JS Console]", "window", wv.ID(), "message", strings.Join(logParts, " "))
 		return nil, nil
 	}
 
@@ -82,7 +82,7 @@ func init() {
 		for _, arg := range args {
 			logParts = append(logParts, fmt.Sprintf("%v", arg))
 		}
-		slog.Debug("[JS Console]", "message", strings.Join(logParts, " "))
+		slog.Debug("[JS Console]", "window", wv.ID(), "message", strings.Join(logParts, " "))
 		return nil, nil
 	}
 
@@ -91,7 +91,7 @@ func init() {
 		for _, arg := range args {
 			logParts = append(logParts, fmt.Sprintf("%v", arg))
 		}
-		slog.Info("[JS Console]", "message", strings.Join(logParts, " "))
+		slog.Info("[JS Console]", "window", wv.ID(), "message", strings.Join(logParts, " "))
 		return nil, nil
 	}
 	UserFunctionRegistry["_go_runtime_setTitle"] = func(ctx context.Context, wv *Webview, args []any) (result any, err error) {
@@ -190,4 +190,279 @@ func init() {
 		wv.Terminate()
 		return nil, nil
 	}
+
+	UserFunctionRegistry["_go_runtime_setAlwaysOnTop"] = func(ctx context.Context, wv *Webview, args []any) (result any, err error) {
+		if len(args) < 1 {
+			return nil, fmt.Errorf("missing alwaysOnTop argument")
+		}
+		onTop, ok := args[0].(bool)
+		if !ok {
+			return nil, fmt.Errorf("invalid alwaysOnTop argument")
+		}
+		return nil, wv.SetAlwaysOnTop(onTop)
+	}
+
+	UserFunctionRegistry["_go_runtime_dialogOpenFile"] = func(ctx context.Context, wv *Webview, args []any) (result any, err error) {
+		opts, err := fileDialogOptionsFromJS(wv, args)
+		if err != nil {
+			return nil, err
+		}
+		dialogCtx, cancel := windowDialogContext(ctx, wv)
+		defer cancel()
+		return OpenFileDialog(dialogCtx, opts)
+	}
+
+	UserFunctionRegistry["_go_runtime_dialogSaveFile"] = func(ctx context.Context, wv *Webview, args []any) (result any, err error) {
+		opts, err := fileDialogOptionsFromJS(wv, args)
+		if err != nil {
+			return nil, err
+		}
+		dialogCtx, cancel := windowDialogContext(ctx, wv)
+		defer cancel()
+		path, err := SaveFileDialog(dialogCtx, opts)
+		if err != nil || path == "" {
+			return nil, err
+		}
+		return path, nil
+	}
+
+	UserFunctionRegistry["_go_runtime_dialogSelectDirectory"] = func(ctx context.Context, wv *Webview, args []any) (result any, err error) {
+		opts, err := fileDialogOptionsFromJS(wv, args)
+		if err != nil {
+			return nil, err
+		}
+		dialogCtx, cancel := windowDialogContext(ctx, wv)
+		defer cancel()
+		path, err := SelectDirectoryDialog(dialogCtx, opts)
+		if err != nil || path == "" {
+			return nil, err
+		}
+		return path, nil
+	}
+
+	UserFunctionRegistry["_go_runtime_dialogMessage"] = func(ctx context.Context, wv *Webview, args []any) (result any, err error) {
+		opts, err := messageDialogOptionsFromJS(wv, args)
+		if err != nil {
+			return nil, err
+		}
+		dialogCtx, cancel := windowDialogContext(ctx, wv)
+		defer cancel()
+		return MessageDialog(dialogCtx, opts)
+	}
+
+	UserFunctionRegistry["_go_runtime_showContextMenu"] = func(ctx context.Context, wv *Webview, args []any) (result any, err error) {
+		if len(args) < 3 {
+			return nil, fmt.Errorf("missing context menu arguments")
+		}
+		name, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid context menu name")
+		}
+		x, xOk := args[1].(float64)
+		y, yOk := args[2].(float64)
+		if !xOk || !yOk {
+			return nil, fmt.Errorf("invalid context menu position")
+		}
+		var data any
+		if len(args) > 3 {
+			data = args[3]
+		}
+		return nil, wv.ShowContextMenu(name, int(x), int(y), data)
+	}
+
+	UserFunctionRegistry["_go_runtime_clipboardReadText"] = func(ctx context.Context, wv *Webview, args []any) (result any, err error) {
+		return Clipboard.ReadText()
+	}
+
+	UserFunctionRegistry["_go_runtime_clipboardWriteText"] = func(ctx context.Context, wv *Webview, args []any) (result any, err error) {
+		if len(args) < 1 {
+			return nil, fmt.Errorf("missing text argument")
+		}
+		text, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid text argument")
+		}
+		return nil, Clipboard.WriteText(text)
+	}
+
+	UserFunctionRegistry["_go_runtime_clipboardReadHTML"] = func(ctx context.Context, wv *Webview, args []any) (result any, err error) {
+		return Clipboard.ReadHTML()
+	}
+
+	UserFunctionRegistry["_go_runtime_clipboardWriteHTML"] = func(ctx context.Context, wv *Webview, args []any) (result any, err error) {
+		if len(args) < 1 {
+			return nil, fmt.Errorf("missing html argument")
+		}
+		html, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid html argument")
+		}
+		return nil, Clipboard.WriteHTML(html)
+	}
+
+	UserFunctionRegistry["_go_runtime_clipboardReadImage"] = func(ctx context.Context, wv *Webview, args []any) (result any, err error) {
+		png, err := Clipboard.ReadImage()
+		if err != nil || len(png) == 0 {
+			return nil, err
+		}
+		return clipboardImageDataURL(png), nil
+	}
+
+	UserFunctionRegistry["_go_runtime_clipboardWriteImage"] = func(ctx context.Context, wv *Webview, args []any) (result any, err error) {
+		if len(args) < 1 {
+			return nil, fmt.Errorf("missing image argument")
+		}
+		s, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid image argument")
+		}
+		png, err := clipboardImageFromJS(s)
+		if err != nil {
+			return nil, err
+		}
+		return nil, Clipboard.WriteImage(png)
+	}
+
+	UserFunctionRegistry["_go_runtime_notify"] = func(ctx context.Context, wv *Webview, args []any) (result any, err error) {
+		return notifyFromJS(wv, args)
+	}
+
+	UserFunctionRegistry["_go_runtime_clearData"] = func(ctx context.Context, wv *Webview, args []any) (result any, err error) {
+		names := []string{"all"}
+		if len(args) > 0 && args[0] != nil {
+			list, ok := args[0].([]any)
+			if !ok {
+				return nil, fmt.Errorf("invalid data types argument")
+			}
+			names = names[:0]
+			for _, v := range list {
+				name, ok := v.(string)
+				if !ok {
+					return nil, fmt.Errorf("invalid data types argument")
+				}
+				names = append(names, name)
+			}
+		}
+		types, err := ParseDataType(names...)
+		if err != nil {
+			return nil, err
+		}
+		return nil, wv.Data().Clear(ctx, types)
+	}
+
+	UserFunctionRegistry["_go_runtime_cancelDownload"] = func(ctx context.Context, wv *Webview, args []any) (result any, err error) {
+		return nil, cancelDownloadFromJS(wv, args)
+	}
+
+	UserFunctionRegistry["_go_runtime_downloads"] = func(ctx context.Context, wv *Webview, args []any) (result any, err error) {
+		list := []downloadInfo{}
+		for _, d := range wv.Downloads() {
+			list = append(list, d.pageInfo(wv))
+		}
+		return list, nil
+	}
+
+	UserFunctionRegistry["_go_runtime_queryPermission"] = func(ctx context.Context, wv *Webview, args []any) (result any, err error) {
+		return queryPermissionFromJS(wv, args)
+	}
+
+	UserFunctionRegistry["_go_runtime_print"] = func(ctx context.Context, wv *Webview, args []any) (result any, err error) {
+		opts, err := printOptionsFromJS(args)
+		if err != nil {
+			return nil, err
+		}
+		return nil, wv.Print(opts)
+	}
+
+	UserFunctionRegistry["_go_runtime_printToPDF"] = func(ctx context.Context, wv *Webview, args []any) (result any, err error) {
+		opts, err := printOptionsFromJS(args)
+		if err != nil {
+			return nil, err
+		}
+		pdf, err := wv.PrintToPDF(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		return pdfDataURL(pdf), nil
+	}
+
+	UserFunctionRegistry["_go_runtime_saveAsPDF"] = func(ctx context.Context, wv *Webview, args []any) (result any, err error) {
+		dialogCtx, cancel := windowDialogContext(ctx, wv)
+		defer cancel()
+		path, err := saveAsPDFFromJS(dialogCtx, wv, args)
+		if err != nil || path == "" {
+			return nil, err
+		}
+		return path, nil
+	}
+
+	UserFunctionRegistry["_go_runtime_capture"] = func(ctx context.Context, wv *Webview, args []any) (result any, err error) {
+		rect, err := captureRectFromJS(args)
+		if err != nil {
+			return nil, err
+		}
+		png, err := wv.CapturePNG(ctx, rect)
+		if err != nil {
+			return nil, err
+		}
+		return clipboardImageDataURL(png), nil
+	}
+
+	UserFunctionRegistry["_go_runtime_setZoom"] = func(ctx context.Context, wv *Webview, args []any) (result any, err error) {
+		if len(args) < 1 {
+			return nil, fmt.Errorf("missing zoom level")
+		}
+		level, ok := args[0].(float64)
+		if !ok {
+			return nil, fmt.Errorf("invalid zoom level")
+		}
+		return nil, wv.SetZoom(float32(level))
+	}
+
+	UserFunctionRegistry["_go_runtime_getZoom"] = func(ctx context.Context, wv *Webview, args []any) (result any, err error) {
+		return wv.Zoom()
+	}
+
+	UserFunctionRegistry["_go_runtime_focusWindow"] = func(ctx context.Context, wv *Webview, args []any) (result any, err error) {
+		return nil, wv.Focus()
+	}
+
+	UserFunctionRegistry["_go_runtime_getWindowState"] = func(ctx context.Context, wv *Webview, args []any) (result any, err error) {
+		return wv.State()
+	}
+
+	UserFunctionRegistry["_go_runtime_getSize"] = func(ctx context.Context, wv *Webview, args []any) (result any, err error) {
+		width, height, err := wv.Size()
+		if err != nil {
+			return nil, err
+		}
+		return map[string]int{"width": width, "height": height}, nil
+	}
+
+	UserFunctionRegistry["_go_runtime_setPosition"] = func(ctx context.Context, wv *Webview, args []any) (result any, err error) {
+		if len(args) < 2 {
+			return nil, fmt.Errorf("missing x or y arguments")
+		}
+		x, ok := args[0].(float64)
+		if !ok {
+			return nil, fmt.Errorf("invalid x argument")
+		}
+		y, ok := args[1].(float64)
+		if !ok {
+			return nil, fmt.Errorf("invalid y argument")
+		}
+		return nil, wv.SetPosition(int(x), int(y))
+	}
+
+	UserFunctionRegistry["_go_runtime_screens"] = func(ctx context.Context, wv *Webview, args []any) (result any, err error) {
+		return Screens()
+	}
+
+	UserFunctionRegistry["_go_runtime_getPosition"] = func(ctx context.Context, wv *Webview, args []any) (result any, err error) {
+		x, y, err := wv.Position()
+		if err != nil {
+			return nil, err
+		}
+		return map[string]int{"x": x, "y": y}, nil
+	}
 }
@@ -53,9 +53,10 @@ window._rejectWebviewPromise = function(id, error) {
  * @param {string} goFuncName - 要调用的 Go 函数的绑定名称 (例如 "_go_runtime_setTitle")。
  * @param {Array<any>} funcArgs - 调用 Go 函数时传递的参数数组。
  * @param {boolean} [expectResponse=true] - 是否期望从 Go 函数获得响应 (通过 Promise)。
+ * @param {number} [timeoutMs=30000] - 等待响应的超时时间，0 表示不超时 (用于对话框等需要等待用户操作的调用)。
  * @returns {Promise<any> | void} - 如果 expectResponse 为 true，则返回一个 Promise；否则返回 void。
  */
-function goCall(goFuncName, funcArgs = [], expectResponse = false) {
+function goCall(goFuncName, funcArgs = [], expectResponse = false, timeoutMs = 30000) {
     if (typeof window._runtime_invoke !== 'function') {
         const errorMessage = `Webview native bridge (window._runtime_invoke) is not available. Cannot call Go function: ${goFuncName}`;
         if (window._originalConsole && window._originalConsole.error) {
@@ -69,7 +70,10 @@ function goCall(goFuncName, funcArgs = [], expectResponse = false) {
 
     const payload = {
         func: goFuncName, // Go 函数的绑定名称
-        args: funcArgs    // 传递给 Go 函数的参数
+        args: funcArgs,   // 传递给 Go 函数的参数
+        origin: window.location.origin,                      // 调用方来源，Go 端据此做来源校验
+        frame: window === window.top ? 'main' : 'subframe', // 调用方所在 frame
+        url: window.location.href
     };
 
     if (expectResponse) {
@@ -77,12 +81,12 @@ function goCall(goFuncName, funcArgs = [], expectResponse = false) {
             const promiseId = window._webviewPromiseNextId++;
             
             // 添加超时机制防止内存泄漏
-            const timeout = setTimeout(() => {
+            const timeout = timeoutMs > 0 ? setTimeout(() => {
                 if (window._webviewPromises[promiseId]) {
                     delete window._webviewPromises[promiseId];
-                    reject(new Error(`Timeout waiting for response from ${goFuncName} (30s)`));
+                    reject(new Error(`Timeout waiting for response from ${goFuncName} (${timeoutMs / 1000}s)`));
                 }
-            }, 30000); // 30秒超时
+            }, timeoutMs) : null;
             
             window._webviewPromises[promiseId] = { resolve, reject, timeout };
             payload.promiseId = promiseId; // 只有需要响应时才包含 promiseId
@@ -112,7 +116,47 @@ function goCall(goFuncName, funcArgs = [], expectResponse = false) {
 }
 // --- Go Call Helper Function End ---
 
+// --- Go Event Start ---
+// Go 端通过 Webview.Emit 调用，分发给 runtime.On 注册的处理函数
+window._wvappListeners = window._wvappListeners || {};
+function notifyRuntimeListeners(name, detail) {
+    var listeners = (window._wvappListeners[name] || []).slice();
+    listeners.forEach(function(fn) {
+        try {
+            fn(detail);
+        } catch (e) {
+            console.error('Error in runtime event handler for ' + name + ':', e);
+        }
+    });
+}
+window._wvappEmit = function(name, detail) {
+    notifyRuntimeListeners(name, detail);
+    window.dispatchEvent(new CustomEvent('wvapp:' + name, { detail: detail }));
+};
+// 文件拖放：detail 为 { paths, x, y }，补充 target 后通知 runtime.On('filedrop') 订阅者，
+// 并在目标元素上派发可冒泡的 wvapp:filedrop 事件
+window._wvappFileDrop = function(detail) {
+    var target = document.elementFromPoint(detail.x, detail.y) || document.body || document.documentElement;
+    detail.target = target;
+    notifyRuntimeListeners('filedrop', detail);
+    if (target) {
+        target.dispatchEvent(new CustomEvent('wvapp:filedrop', { bubbles: true, detail: detail }));
+    }
+};
+// --- Go Event End ---
+
 window.runtime = {
+    // 订阅 Go 端 Webview.Emit 发送的事件，返回取消订阅函数
+    On: function(name, handler) {
+        var listeners = window._wvappListeners[name] = window._wvappListeners[name] || [];
+        listeners.push(handler);
+        return function() {
+            var i = listeners.indexOf(handler);
+            if (i >= 0) {
+                listeners.splice(i, 1);
+            }
+        };
+    },
     SetTitle: function(title) {
         return goCall('_go_runtime_setTitle', [title]);
     },
@@ -139,8 +183,153 @@ window.runtime = {
     },
     CloseWindow: function() {
         return goCall('_go_runtime_closeWindow', []);
+    },
+    SetAlwaysOnTop: function(onTop) {
+        return goCall('_go_runtime_setAlwaysOnTop', [onTop], true);
+    },
+    FocusWindow: function() {
+        return goCall('_go_runtime_focusWindow', []);
+    },
+    // 在窗口坐标 (x, y) 弹出 Go 端注册的右键菜单，data 会传给菜单项的 OnClick
+    ShowContextMenu: function(name, x, y, data) {
+        return goCall('_go_runtime_showContextMenu', [name, x, y, data === undefined ? null : data], true);
+    },
+    // 当前窗口的稳定 ID（与 Go 端 Webview.ID() 一致），注入前返回 0
+    WindowID: function() {
+        return window._wvappWindowId || 0;
+    },
+    // 以下查询接口均返回 Promise
+    GetWindowState: function() {
+        return goCall('_go_runtime_getWindowState', [], true);
+    },
+    GetSize: function() {
+        return goCall('_go_runtime_getSize', [], true);
+    },
+    GetPosition: function() {
+        return goCall('_go_runtime_getPosition', [], true);
+    },
+    SetPosition: function(x, y) {
+        return goCall('_go_runtime_setPosition', [x, y], true);
+    },
+    Screens: function() {
+        return goCall('_go_runtime_screens', [], true);
+    },
+    GetTitle: function() {
+        return window.runtime.GetWindowState().then(function(s) { return s.title; });
+    },
+    GetURL: function() {
+        return window.runtime.GetWindowState().then(function(s) { return s.url; });
+    },
+    IsMaximized: function() {
+        return window.runtime.GetWindowState().then(function(s) { return s.maximized; });
+    },
+    IsMinimized: function() {
+        return window.runtime.GetWindowState().then(function(s) { return s.minimized; });
+    },
+    IsFullscreen: function() {
+        return window.runtime.GetWindowState().then(function(s) { return s.fullscreen; });
+    },
+    IsVisible: function() {
+        return window.runtime.GetWindowState().then(function(s) { return s.visible; });
+    },
+    // 显示桌面通知，resolve 为通知 ID；用户点击通知或按钮时触发 runtime.On('notification', fn)，
+    // fn 收到 { id, action }，点击通知本身时 action 为空字符串
+    // options: { id, title, body, icon (PNG data URL), actions: [{id, label}] }
+    Notify: function(options) {
+        return goCall('_go_runtime_notify', [options], true);
+    },
+    // 系统剪贴板，不受页面用户手势限制；图片以 PNG data URL 表示，没有图片时 resolve 为 null
+    Clipboard: {
+        ReadText: function() {
+            return goCall('_go_runtime_clipboardReadText', [], true);
+        },
+        WriteText: function(text) {
+            return goCall('_go_runtime_clipboardWriteText', [String(text)], true);
+        },
+        ReadHTML: function() {
+            return goCall('_go_runtime_clipboardReadHTML', [], true);
+        },
+        WriteHTML: function(html) {
+            return goCall('_go_runtime_clipboardWriteHTML', [String(html)], true);
+        },
+        ReadImage: function() {
+            return goCall('_go_runtime_clipboardReadImage', [], true);
+        },
+        WriteImage: function(dataURL) {
+            return goCall('_go_runtime_clipboardWriteImage', [dataURL], true);
+        }
+    },
+    // 清除当前窗口数据存储中的网站数据，例如退出登录时调用；
+    // types 为 'cookies'、'cache'、'localStorage'、'sessionStorage'、'indexedDB'、'serviceWorkers' 组成的数组，省略时清除全部
+    ClearData: function(types) {
+        return goCall('_go_runtime_clearData', [types || null], true);
+    },
+    // 打印与导出 PDF，options: { pageSize: 'A4' | 'Letter' | {width, height}（毫米）,
+    // margins: {top, right, bottom, left}（毫米）, landscape, printBackground, scale }
+    Print: function(options) {
+        return goCall('_go_runtime_print', [options || null], true);
+    },
+    // resolve 为 data:application/pdf;base64,... 形式的 PDF
+    PrintToPDF: function(options) {
+        return goCall('_go_runtime_printToPDF', [options || null], true);
+    },
+    // 导出 PDF 后弹出保存对话框，resolve 为保存路径，取消时为 null
+    SaveAsPDF: function(options, fileName) {
+        return goCall('_go_runtime_saveAsPDF', [options || null, fileName || ''], true, 0);
+    },
+    // 页面缩放级别，1 表示 100%（范围 0.25-5）
+    SetZoom: function(level) {
+        return goCall('_go_runtime_setZoom', [level], true);
+    },
+    GetZoom: function() {
+        return goCall('_go_runtime_getZoom', [], true);
+    },
+    // 截取当前页面，area 为 'viewport'（默认）、'fullPage' 或 {x, y, width, height}（页面坐标，CSS 像素），
+    // resolve 为 PNG data URL
+    Capture: function(area) {
+        return goCall('_go_runtime_capture', [area || null], true);
+    },
+    // 返回当前页面来源对某项权限（'camera'、'microphone'、'geolocation'、'notifications' 等）
+    // 已记住的决定：'granted'、'denied' 或 'prompt'
+    QueryPermission: function(kind) {
+        return goCall('_go_runtime_queryPermission', [kind], true);
+    },
+    // 下载事件：runtime.On('downloadstarted' | 'downloadprogress' | 'downloadfinished', fn)，
+    // fn 收到 { id, url, suggestedName, mimeType, path, received, total, state, error }
+    CancelDownload: function(id) {
+        return goCall('_go_runtime_cancelDownload', [id], true);
+    },
+    // 返回当前窗口进行中的下载
+    Downloads: function() {
+        return goCall('_go_runtime_downloads', [], true);
+    },
+    // 原生文件对话框，等待用户操作不设超时；取消时 resolve 为 null
+    // options: { title, defaultPath, filters: [{name, patterns}], multiSelect, showHidden }
+    Dialog: {
+        OpenFile: function(options) {
+            return goCall('_go_runtime_dialogOpenFile', [options || {}], true, 0);
+        },
+        SaveFile: function(options) {
+            return goCall('_go_runtime_dialogSaveFile', [options || {}], true, 0);
+        },
+        SelectDirectory: function(options) {
+            return goCall('_go_runtime_dialogSelectDirectory', [options || {}], true, 0);
+        },
+        // kind: 'info' | 'warning' | 'error' | 'question'，返回所选按钮的文字
+        Message: function(options) {
+            return goCall('_go_runtime_dialogMessage', [options], true, 0);
+        }
     }
 };
+// 带有 data-context-menu 属性的元素右键时弹出同名菜单，data-context-menu-data 作为 data 传递
+document.addEventListener('contextmenu', function(e) {
+    var el = e.target && e.target.closest ? e.target.closest('[data-context-menu]') : null;
+    if (!el) {
+        return;
+    }
+    e.preventDefault();
+    window.runtime.ShowContextMenu(el.dataset.contextMenu, e.clientX, e.clientY, el.dataset.contextMenuData);
+});
 // From: https://stackoverflow.com/questions/105034/how-to-create-a-guid-uuid
 function uuidv4(){
     return "10000000-1000-4000-8000-100000000000".replace(/[018]/g, (c) =>
@@ -0,0 +1,134 @@
+package wvapp
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Rect 屏幕坐标系中的矩形（逻辑像素）
+type Rect struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// Intersect 返回两个矩形的交集，不相交时返回空矩形
+func (r Rect) Intersect(o Rect) Rect {
+	x0, y0 := max(r.X, o.X), max(r.Y, o.Y)
+	x1, y1 := min(r.X+r.Width, o.X+o.Width), min(r.Y+r.Height, o.Y+o.Height)
+	if x1 <= x0 || y1 <= y0 {
+		return Rect{}
+	}
+	return Rect{X: x0, Y: y0, Width: x1 - x0, Height: y1 - y0}
+}
+
+// Empty 矩形是否为空
+func (r Rect) Empty() bool {
+	return r.Width <= 0 || r.Height <= 0
+}
+
+// Screen 显示器信息
+type Screen struct {
+	Name        string  `json:"name"`
+	Bounds      Rect    `json:"bounds"`   // 显示器完整区域
+	WorkArea    Rect    `json:"workArea"` // 去除任务栏/菜单栏后的可用区域
+	ScaleFactor float64 `json:"scaleFactor"`
+	Primary     bool    `json:"primary"`
+}
+
+// usableArea 返回可用于放置窗口的区域，原生库未提供工作区时使用完整区域
+func (s Screen) usableArea() Rect {
+	if s.WorkArea.Empty() {
+		return s.Bounds
+	}
+	return s.WorkArea
+}
+
+type screensResult struct {
+	screens []Screen
+	err     error
+}
+
+// Screens 返回当前连接的显示器列表（通过主线程调度器同步查询）
+func Screens() ([]Screen, error) {
+	if err := loadWebviewLibrary(); err != nil {
+		return nil, err
+	}
+	if webviewGetScreens == nil || webviewFreeString == nil {
+		return nil, ErrNotSupported
+	}
+	res := mainScheduler.RunInMainThreadWithResult(func() any {
+		ptr := webviewGetScreens()
+		if ptr == 0 {
+			return screensResult{err: fmt.Errorf("webview: failed to query screens")}
+		}
+		data := goString(ptr)
+		webviewFreeString(ptr)
+		var screens []Screen
+		if err := json.Unmarshal([]byte(data), &screens); err != nil {
+			return screensResult{err: fmt.Errorf("webview: invalid screen list: %w", err)}
+		}
+		return screensResult{screens: screens}
+	}).(screensResult)
+	return res.screens, res.err
+}
+
+// SetPosition 将窗口左上角移动到屏幕坐标 (x, y)
+func (w *Webview) SetPosition(x, y int) error {
+	if webviewSetPosition == nil {
+		return ErrNotSupported
+	}
+	mainScheduler.RunInMainThread(func() { webviewSetPosition(w, x, y) })
+	return nil
+}
+
+// placeInArea 计算窗口在区域 area 中按 position 放置时的左上角坐标
+func placeInArea(position WindowPosition, offsetX, offsetY, width, height int, area Rect) (x, y int) {
+	switch position {
+	case WindowPositionLeftTop:
+		return area.X, area.Y
+	case WindowPositionRightTop:
+		return area.X + area.Width - width, area.Y
+	case WindowPositionLeftBottom:
+		return area.X, area.Y + area.Height - height
+	case WindowPositionRightBottom:
+		return area.X + area.Width - width, area.Y + area.Height - height
+	case WindowPositionCustom:
+		return area.X + offsetX, area.Y + offsetY
+	default:
+		return area.X + (area.Width-width)/2, area.Y + (area.Height-height)/2
+	}
+}
+
+// placementArea 返回窗口选项对应的参照区域，ok 为 false 表示交给原生库按 Position 放置。
+// 未指定 RelativeTo 时子窗口相对父窗口放置。
+func placementArea(options *WindowOptions) (Rect, bool, error) {
+	relativeTo := options.RelativeTo
+	if relativeTo == nil {
+		relativeTo = options.Parent
+	}
+	switch {
+	case relativeTo != nil:
+		s, err := relativeTo.State()
+		if err != nil {
+			return Rect{}, false, err
+		}
+		return Rect{X: s.X, Y: s.Y, Width: s.Width, Height: s.Height}, true, nil
+	case options.Screen != nil:
+		return options.Screen.usableArea(), true, nil
+	case options.Position == WindowPositionCustom:
+		return Rect{}, true, nil
+	}
+	return Rect{}, false, nil
+}
+
+// applyPlacement 根据 X/Y、Screen 与 RelativeTo 调整新窗口的位置
+func (w *Webview) applyPlacement(options *WindowOptions) error {
+	area, ok, err := placementArea(options)
+	if err != nil || !ok {
+		return err
+	}
+	x, y := placeInArea(options.Position, options.X, options.Y, options.Width, options.Height, area)
+	return w.SetPosition(x, y)
+}
@@ -0,0 +1,67 @@
+package wvapp
+
+import "testing"
+
+func TestRectIntersect(t *testing.T) {
+	a := Rect{X: 0, Y: 0, Width: 100, Height: 100}
+	if got := a.Intersect(Rect{X: 50, Y: 60, Width: 100, Height: 100}); got != (Rect{X: 50, Y: 60, Width: 50, Height: 40}) {
+		t.Errorf("unexpected intersection %+v", got)
+	}
+	if got := a.Intersect(Rect{X: 100, Y: 0, Width: 10, Height: 10}); !got.Empty() {
+		t.Errorf("touching rects should not intersect, got %+v", got)
+	}
+}
+
+func TestPlaceInArea(t *testing.T) {
+	area := Rect{X: 1920, Y: 0, Width: 1280, Height: 1000}
+	cases := []struct {
+		position WindowPosition
+		x, y     int
+	}{
+		{WindowPositionCenter, 1920 + 240, 200},
+		{WindowPositionLeftTop, 1920, 0},
+		{WindowPositionRightTop, 1920 + 480, 0},
+		{WindowPositionLeftBottom, 1920, 400},
+		{WindowPositionRightBottom, 1920 + 480, 400},
+		{WindowPositionCustom, 1920 + 15, 25},
+	}
+	for _, c := range cases {
+		x, y := placeInArea(c.position, 15, 25, 800, 600, area)
+		if x != c.x || y != c.y {
+			t.Errorf("position %d: got (%d, %d), want (%d, %d)", c.position, x, y, c.x, c.y)
+		}
+	}
+}
+
+func TestPlacementArea(t *testing.T) {
+	screen := &Screen{Bounds: Rect{X: 0, Y: 0, Width: 1920, Height: 1080}, WorkArea: Rect{X: 0, Y: 30, Width: 1920, Height: 1050}}
+	if area, ok, err := placementArea(&WindowOptions{Screen: screen}); err != nil || !ok || area != screen.WorkArea {
+		t.Errorf("screen placement: %+v %v %v", area, ok, err)
+	}
+	if area, ok, err := placementArea(&WindowOptions{Position: WindowPositionCustom}); err != nil || !ok || area != (Rect{}) {
+		t.Errorf("custom placement should use absolute coordinates: %+v %v %v", area, ok, err)
+	}
+	if _, ok, err := placementArea(&WindowOptions{Position: WindowPositionRightTop}); err != nil || ok {
+		t.Errorf("preset positions without screen should be left to the native library: %v %v", ok, err)
+	}
+}
+
+func TestSetWindowPositionIgnoresCustom(t *testing.T) {
+	original := webviewSetWindowPosition
+	defer func() {
+		mainScheduler.PollTasks()
+		webviewSetWindowPosition = original
+	}()
+	var sent []WindowPosition
+	webviewSetWindowPosition = func(_ *Webview, position WindowPosition) { sent = append(sent, position) }
+	mainScheduler.PollTasks()
+
+	wv := fakeWebview()
+	for _, position := range []WindowPosition{WindowPositionCustom, WindowPosition(-1), WindowPosition(99), WindowPositionRightTop} {
+		wv.SetWindowPosition(position)
+	}
+	mainScheduler.PollTasks()
+	if len(sent) != 1 || sent[0] != WindowPositionRightTop {
+		t.Fatalf("only preset positions should reach the native library, got %v", sent)
+	}
+}
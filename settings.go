@@ -0,0 +1,161 @@
+package wvapp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// AutoplayPolicy 媒体自动播放策略
+type AutoplayPolicy string
+
+const (
+	AutoplayDefault            AutoplayPolicy = ""             // 保持当前策略
+	AutoplayAllow              AutoplayPolicy = "allow"        // 允许带声音的自动播放
+	AutoplayRequireUserGesture AutoplayPolicy = "user-gesture" // 带声音的媒体需要用户操作后才能播放，静音媒体可以自动播放
+	AutoplayDeny               AutoplayPolicy = "deny"         // 禁止自动播放
+)
+
+// HardwareAcceleration 硬件加速策略
+type HardwareAcceleration string
+
+const (
+	HardwareAccelerationDefault  HardwareAcceleration = ""          // 保持当前策略
+	HardwareAccelerationAlways   HardwareAcceleration = "always"    // 始终使用 GPU 合成
+	HardwareAccelerationNever    HardwareAcceleration = "never"     // 软件渲染，例如在有问题的显卡驱动或虚拟机上
+	HardwareAccelerationOnDemand HardwareAcceleration = "on-demand" // 由引擎按页面内容决定
+)
+
+// maxFontSize 字号设置的上限（像素）
+const maxFontSize = 72
+
+// WebSettings 网页设置，零值字段表示保持当前值，因此运行时可以只修改部分设置
+type WebSettings struct {
+	UserAgent                string               `json:"userAgent,omitempty"` // 为空表示保持当前值，恢复默认请用 SetUserAgent("")
+	JavaScript               *bool                `json:"javascript,omitempty"`
+	Autoplay                 AutoplayPolicy       `json:"autoplay,omitempty"`
+	SpellCheck               *bool                `json:"spellCheck,omitempty"`
+	DefaultFontSize          int                  `json:"defaultFontSize,omitempty"`          // 像素
+	DefaultMonospaceFontSize int                  `json:"defaultMonospaceFontSize,omitempty"` // 像素
+	MinimumFontSize          int                  `json:"minimumFontSize,omitempty"`          // 像素，-1 表示取消最小字号
+	HardwareAcceleration     HardwareAcceleration `json:"hardwareAcceleration,omitempty"`     // 创建窗口时在创建之前设置；运行时修改在部分平台上不支持
+	WebGL                    *bool                `json:"webgl,omitempty"`
+	FileAccess               *bool                `json:"fileAccess,omitempty"` // file:// 页面访问其他本地文件
+	Clipboard                *bool                `json:"clipboard,omitempty"`  // 页面通过 navigator.clipboard 访问剪贴板
+}
+
+// Bool 返回 v 的指针，用于设置 WebSettings 中的可选字段
+func Bool(v bool) *bool {
+	return &v
+}
+
+func (s WebSettings) isZero() bool {
+	return s == WebSettings{}
+}
+
+func (s WebSettings) validate() error {
+	if strings.ContainsFunc(s.UserAgent, func(r rune) bool { return r < 0x20 || r == 0x7f }) {
+		return fmt.Errorf("webview: user agent contains control characters")
+	}
+	switch s.Autoplay {
+	case AutoplayDefault, AutoplayAllow, AutoplayRequireUserGesture, AutoplayDeny:
+	default:
+		return fmt.Errorf("webview: invalid autoplay policy %q", s.Autoplay)
+	}
+	switch s.HardwareAcceleration {
+	case HardwareAccelerationDefault, HardwareAccelerationAlways, HardwareAccelerationNever, HardwareAccelerationOnDemand:
+	default:
+		return fmt.Errorf("webview: invalid hardware acceleration policy %q", s.HardwareAcceleration)
+	}
+	for name, size := range map[string]int{"default": s.DefaultFontSize, "default monospace": s.DefaultMonospaceFontSize} {
+		if size < 0 || size > maxFontSize {
+			return fmt.Errorf("webview: %s font size %d is outside 1-%d", name, size, maxFontSize)
+		}
+	}
+	if s.MinimumFontSize < -1 || s.MinimumFontSize > maxFontSize {
+		return fmt.Errorf("webview: minimum font size %d is outside 1-%d", s.MinimumFontSize, maxFontSize)
+	}
+	return nil
+}
+
+// splitCreation 拆分出需要在创建窗口之前设置的硬件加速策略，其余字段在创建后应用
+func (s WebSettings) splitCreation() (HardwareAcceleration, WebSettings) {
+	hardware := s.HardwareAcceleration
+	s.HardwareAcceleration = HardwareAccelerationDefault
+	return hardware, s
+}
+
+// checkSupported 创建窗口前确认原生库能应用全部非零字段，避免设置被静默忽略
+func (s WebSettings) checkSupported() error {
+	hardware, rest := s.splitCreation()
+	if hardware != HardwareAccelerationDefault && webviewSetHardwareAcceleration == nil {
+		return fmt.Errorf("webview: WebSettings.HardwareAcceleration: %w", ErrNotSupported)
+	}
+	if !rest.isZero() && (webviewApplySettings == nil || webviewFreeString == nil) {
+		return fmt.Errorf("webview: WebSettings: %w", ErrNotSupported)
+	}
+	return nil
+}
+
+// SetWebSettings 修改网页设置，只有非零值字段会被应用
+func (w *Webview) SetWebSettings(s WebSettings) error {
+	if err := s.validate(); err != nil {
+		return err
+	}
+	if s.isZero() {
+		return nil
+	}
+	return w.applySettings(s)
+}
+
+// SetUserAgent 设置之后请求使用的 User-Agent，空字符串恢复引擎默认值
+func (w *Webview) SetUserAgent(userAgent string) error {
+	if err := (WebSettings{UserAgent: userAgent}).validate(); err != nil {
+		return err
+	}
+	return w.applySettings(map[string]string{"userAgent": userAgent})
+}
+
+// SetZoom 设置页面缩放级别（1 表示 100%），与 WindowOptions.ZoomLevel 含义相同
+func (w *Webview) SetZoom(level float32) error {
+	if level < 0.25 || level > 5 {
+		return fmt.Errorf("webview: zoom level %g is outside 0.25-5", level)
+	}
+	return w.applySettings(map[string]float32{"zoom": level})
+}
+
+// Zoom 返回页面当前的缩放级别（1 表示 100%）
+func (w *Webview) Zoom() (float32, error) {
+	s, err := w.State()
+	return s.Zoom, err
+}
+
+// applySettings 原生库返回 0 表示成功，否则返回需要用 webviewFreeString 释放的错误描述
+func (w *Webview) applySettings(settings any) error {
+	if w == nil {
+		return fmt.Errorf("webview: nil webview")
+	}
+	if webviewApplySettings == nil || webviewFreeString == nil {
+		return ErrNotSupported
+	}
+	data, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+	msg := mainScheduler.RunInMainThreadWithResult(func() any {
+		cstr, ptr := cString(string(data))
+		result := webviewApplySettings(w, ptr)
+		runtime.KeepAlive(cstr)
+		if result == 0 {
+			return ""
+		}
+		defer webviewFreeString(result)
+		return goString(result)
+	}).(string)
+	if msg != "" {
+		return errors.New("webview: " + msg)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package wvapp
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestWebSettingsValidate(t *testing.T) {
+	ok := WebSettings{
+		UserAgent:                "ReportViewer/2.1",
+		JavaScript:               Bool(true),
+		Autoplay:                 AutoplayRequireUserGesture,
+		SpellCheck:               Bool(false),
+		DefaultFontSize:          16,
+		DefaultMonospaceFontSize: 13,
+		MinimumFontSize:          -1,
+		HardwareAcceleration:     HardwareAccelerationNever,
+		WebGL:                    Bool(false),
+	}
+	if err := ok.validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	for name, s := range map[string]WebSettings{
+		"user agent newline": {UserAgent: "a\r\nX-Injected: 1"},
+		"autoplay":           {Autoplay: "sometimes"},
+		"hardware":           {HardwareAcceleration: "gpu"},
+		"font size":          {DefaultFontSize: 200},
+		"monospace size":     {DefaultMonospaceFontSize: -3},
+		"minimum size":       {MinimumFontSize: -2},
+	} {
+		if err := s.validate(); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestWebSettingsJSONOmitsUnsetFields(t *testing.T) {
+	data, err := json.Marshal(WebSettings{JavaScript: Bool(false), DefaultFontSize: 18})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"javascript":false,"defaultFontSize":18}` {
+		t.Fatalf("json = %s", data)
+	}
+	if !(WebSettings{}).isZero() || (WebSettings{WebGL: Bool(false)}).isZero() {
+		t.Fatal("isZero is wrong")
+	}
+}
+
+func TestSettingsWithoutNativeSupport(t *testing.T) {
+	saved := webviewApplySettings
+	webviewApplySettings = nil
+	defer func() { webviewApplySettings = saved }()
+
+	wv := fakeWebview()
+	if err := wv.SetWebSettings(WebSettings{}); err != nil {
+		t.Fatalf("empty settings: %v", err)
+	}
+	if err := wv.SetWebSettings(WebSettings{Autoplay: AutoplayDeny}); !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("SetWebSettings = %v, want ErrNotSupported", err)
+	}
+	if err := wv.SetZoom(10); err == nil || errors.Is(err, ErrNotSupported) {
+		t.Fatalf("SetZoom(10) = %v, want a range error", err)
+	}
+	if err := wv.SetUserAgent("bad\nagent"); err == nil || errors.Is(err, ErrNotSupported) {
+		t.Fatalf("SetUserAgent = %v, want a validation error", err)
+	}
+}
+
+func TestWebSettingsCheckSupported(t *testing.T) {
+	savedApply, savedHardware := webviewApplySettings, webviewSetHardwareAcceleration
+	defer func() { webviewApplySettings, webviewSetHardwareAcceleration = savedApply, savedHardware }()
+	webviewApplySettings, webviewSetHardwareAcceleration = nil, nil
+
+	if err := (WebSettings{}).checkSupported(); err != nil {
+		t.Fatalf("empty settings: %v", err)
+	}
+	for name, s := range map[string]WebSettings{
+		"javascript": {JavaScript: Bool(false)},
+		"hardware":   {HardwareAcceleration: HardwareAccelerationNever},
+	} {
+		if err := s.checkSupported(); !errors.Is(err, ErrNotSupported) {
+			t.Errorf("%s: checkSupported = %v, want ErrNotSupported", name, err)
+		}
+	}
+
+	webviewSetHardwareAcceleration = func(uintptr) {}
+	hardware, rest := WebSettings{HardwareAcceleration: HardwareAccelerationNever}.splitCreation()
+	if hardware != HardwareAccelerationNever || !rest.isZero() {
+		t.Fatalf("splitCreation = %q, %+v", hardware, rest)
+	}
+	if err := (WebSettings{HardwareAcceleration: HardwareAccelerationNever}).checkSupported(); err != nil {
+		t.Fatalf("hardware acceleration before creation: %v", err)
+	}
+}
+
+func TestNewCWindowOptionsPassesFeatureFlags(t *testing.T) {
+	c := newCWindowOptions(&WindowOptions{
+		Width:            640,
+		Height:           480,
+		Position:         WindowPositionCustom,
+		EnableFileAccess: true,
+		EnableClipboard:  true,
+		EnableWebGL:      true,
+	}, 0, 0, 0)
+	if !c.enableFileAccess || !c.enableClipboard || !c.enableWebGL {
+		t.Fatalf("feature flags not passed: %+v", c)
+	}
+	if c.width != 640 || c.height != 480 || c.position != int32(WindowPositionCenter) {
+		t.Fatalf("options = %+v", c)
+	}
+}
@@ -0,0 +1,226 @@
+package wvapp
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	singleInstanceDialTimeout = 2 * time.Second // 主实例已持有锁但尚未开始监听时的等待上限
+	singleInstanceIOTimeout   = 5 * time.Second
+	maxSocketPathLength       = 100 // sockaddr_un.sun_path 在 macOS 上只有 104 字节
+)
+
+var validAppID = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// errLocked 锁文件已被其他进程持有
+var errLocked = errors.New("lock is held by another process")
+
+// secondInstanceMessage 第二个实例发送给主实例的数据
+type secondInstanceMessage struct {
+	Args []string `json:"args"`
+	Cwd  string   `json:"cwd"`
+}
+
+// singleInstanceLock 主实例持有的锁与监听器
+type singleInstanceLock struct {
+	lock     io.Closer
+	listener net.Listener
+	path     string
+}
+
+var (
+	singleInstances      = make(map[string]*singleInstanceLock)
+	singleInstancesMutex sync.Mutex
+)
+
+// SingleInstance 保证同一 appID 只有一个实例运行，应在创建窗口之前调用。
+//
+// 当前进程成为主实例时返回 nil，之后每当有新实例启动，onSecondInstance 会在后台 goroutine 中
+// 收到新实例的参数（不含程序名）与工作目录，通常用于聚焦窗口或打开文件。
+// 已有主实例时，参数被转发给主实例，当前进程以状态码 0 退出。
+func SingleInstance(appID string, onSecondInstance func(args []string, cwd string)) error {
+	cwd, _ := os.Getwd()
+	primary, err := singleInstance(appID, secondInstanceMessage{Args: os.Args[1:], Cwd: cwd}, onSecondInstance)
+	if err != nil {
+		return err
+	}
+	if !primary {
+		os.Exit(0)
+	}
+	return nil
+}
+
+// singleInstance 成为主实例返回 true；否则把 msg 转发给主实例并返回 false
+func singleInstance(appID string, msg secondInstanceMessage, handler func(args []string, cwd string)) (bool, error) {
+	if !validAppID.MatchString(appID) {
+		return false, fmt.Errorf("webview: invalid app ID %q", appID)
+	}
+	singleInstancesMutex.Lock()
+	defer singleInstancesMutex.Unlock()
+	if _, ok := singleInstances[appID]; ok {
+		return true, nil
+	}
+
+	dir, err := singleInstanceDir()
+	if err != nil {
+		return false, err
+	}
+	socketPath := singleInstanceSocketPath(dir, appID)
+	lock, err := tryLockFile(socketPath + ".lock")
+	if errors.Is(err, errLocked) {
+		return false, forwardToPrimary(socketPath, msg)
+	}
+	if err != nil {
+		return false, fmt.Errorf("webview: single instance lock: %w", err)
+	}
+
+	// 持有锁说明之前的主实例已退出，残留的 socket 文件可以安全替换
+	listener, err := listenSingleInstance(socketPath)
+	if err != nil {
+		lock.Close()
+		return false, fmt.Errorf("webview: single instance listen: %w", err)
+	}
+	singleInstances[appID] = &singleInstanceLock{lock: lock, listener: listener, path: socketPath}
+	go serveSecondInstances(listener, handler)
+	return true, nil
+}
+
+// releaseSingleInstance 释放主实例锁，进程退出时操作系统也会自动释放
+func releaseSingleInstance(appID string) {
+	singleInstancesMutex.Lock()
+	inst, ok := singleInstances[appID]
+	delete(singleInstances, appID)
+	singleInstancesMutex.Unlock()
+	if !ok {
+		return
+	}
+	inst.listener.Close()
+	os.Remove(inst.path)
+	inst.lock.Close()
+}
+
+func serveSecondInstances(listener net.Listener, handler func(args []string, cwd string)) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				slog.Warn("Single instance listener stopped", "error", err)
+			}
+			return
+		}
+		msg, err := readSecondInstance(conn)
+		if err != nil {
+			slog.Warn("Ignoring invalid second instance message", "error", err)
+			continue
+		}
+		if handler == nil {
+			continue
+		}
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					slog.Error("Panic in second instance handler", "panic", r)
+				}
+			}()
+			handler(msg.Args, msg.Cwd)
+		}()
+	}
+}
+
+func readSecondInstance(conn net.Conn) (secondInstanceMessage, error) {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(singleInstanceIOTimeout))
+	var msg secondInstanceMessage
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil {
+		return msg, err
+	}
+	if err := json.Unmarshal(line, &msg); err != nil {
+		return msg, err
+	}
+	// 确认收到后第二个实例才会退出
+	_, err = conn.Write([]byte("ok\n"))
+	return msg, err
+}
+
+// forwardToPrimary 将参数发送给主实例并等待确认
+func forwardToPrimary(socketPath string, msg secondInstanceMessage) error {
+	var conn net.Conn
+	var err error
+	deadline := time.Now().Add(singleInstanceDialTimeout)
+	for {
+		conn, err = dialSingleInstance(socketPath, singleInstanceIOTimeout)
+		if err == nil || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if err != nil {
+		return fmt.Errorf("webview: cannot reach the running instance: %w", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(singleInstanceIOTimeout))
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if _, err := conn.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("webview: cannot reach the running instance: %w", err)
+	}
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil || reply != "ok\n" {
+		return fmt.Errorf("webview: running instance did not acknowledge arguments: %v", err)
+	}
+	return nil
+}
+
+// singleInstanceDir 用户运行时目录：优先 $XDG_RUNTIME_DIR，其次为用户私有的临时目录。
+// 临时目录可能已被其他用户抢先创建，因此创建后检查其所有者与权限
+func singleInstanceDir() (string, error) {
+	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+		return dir, nil
+	}
+	var dir string
+	if runtime.GOOS == "windows" {
+		cache, err := os.UserCacheDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(cache, "wvapp")
+	} else {
+		dir = filepath.Join(os.TempDir(), "wvapp-"+strconv.Itoa(os.Getuid()))
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	if err := checkPrivateDir(dir); err != nil {
+		return "", fmt.Errorf("webview: unsafe single instance directory %s: %w", dir, err)
+	}
+	return dir, nil
+}
+
+// singleInstanceSocketPath 返回 socket 路径（Windows 上为记录命名管道名称的文件），路径过长时改用 appID 的哈希作为文件名
+func singleInstanceSocketPath(dir, appID string) string {
+	path := filepath.Join(dir, appID+".sock")
+	if len(path) <= maxSocketPathLength {
+		return path
+	}
+	sum := sha256.Sum256([]byte(appID))
+	return filepath.Join(dir, "wvapp-"+hex.EncodeToString(sum[:8])+".sock")
+}
@@ -0,0 +1,114 @@
+package wvapp
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+const singleInstanceHelperEnv = "WVAPP_SINGLE_INSTANCE_HELPER"
+
+// TestSingleInstanceHelper 作为第二个进程运行：转发参数后 SingleInstance 会直接退出进程
+func TestSingleInstanceHelper(t *testing.T) {
+	appID := os.Getenv(singleInstanceHelperEnv)
+	if appID == "" {
+		return
+	}
+	if err := SingleInstance(appID, nil); err != nil {
+		os.Exit(2)
+	}
+	// 成为了主实例
+	os.Exit(3)
+}
+
+func runSecondInstance(t *testing.T, appID, cwd string, args ...string) int {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], append([]string{"-test.run=^TestSingleInstanceHelper$", "--"}, args...)...)
+	cmd.Dir = cwd
+	cmd.Env = append(os.Environ(), singleInstanceHelperEnv+"="+appID)
+	err := cmd.Run()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return exitErr.ExitCode()
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return 0
+}
+
+func TestSingleInstance(t *testing.T) {
+	runtimeDir := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", runtimeDir)
+	appID := "com.example.sync"
+
+	// 没有主实例时，第二个进程自己成为主实例
+	if code := runSecondInstance(t, appID, runtimeDir); code != 3 {
+		t.Fatalf("first process exit code = %d, want 3 (primary)", code)
+	}
+
+	type launch struct {
+		args []string
+		cwd  string
+	}
+	launches := make(chan launch, 1)
+	if err := SingleInstance(appID, func(args []string, cwd string) {
+		launches <- launch{args, cwd}
+	}); err != nil {
+		t.Fatal(err)
+	}
+	defer releaseSingleInstance(appID)
+
+	workDir := t.TempDir()
+	if code := runSecondInstance(t, appID, workDir, "open", "report final.pdf"); code != 0 {
+		t.Fatalf("second instance exit code = %d, want 0", code)
+	}
+	select {
+	case got := <-launches:
+		if i := len(got.args) - 2; i < 0 || !reflect.DeepEqual(got.args[i:], []string{"open", "report final.pdf"}) {
+			t.Fatalf("forwarded args = %q", got.args)
+		}
+		if resolved, _ := filepath.EvalSymlinks(workDir); got.cwd != workDir && got.cwd != resolved {
+			t.Fatalf("forwarded cwd = %q, want %q", got.cwd, workDir)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("primary did not receive the second instance")
+	}
+
+	// 不同 appID 互不影响
+	if code := runSecondInstance(t, "com.example.other", workDir); code != 3 {
+		t.Fatalf("other app exit code = %d, want 3", code)
+	}
+}
+
+func TestSingleInstanceStaleSocket(t *testing.T) {
+	runtimeDir := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", runtimeDir)
+	appID := "com.example.stale"
+
+	// 上一个主实例崩溃后留下的 socket 文件
+	stale := singleInstanceSocketPath(runtimeDir, appID)
+	if err := os.WriteFile(stale, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	primary, err := singleInstance(appID, secondInstanceMessage{}, nil)
+	if err != nil || !primary {
+		t.Fatalf("singleInstance = %v, %v", primary, err)
+	}
+	releaseSingleInstance(appID)
+}
+
+func TestSingleInstanceAppID(t *testing.T) {
+	for _, id := range []string{"", "../evil", "a/b", ".hidden"} {
+		if _, err := singleInstance(id, secondInstanceMessage{}, nil); err == nil {
+			t.Errorf("app ID %q should be rejected", id)
+		}
+	}
+	long := singleInstanceSocketPath("/tmp", strings.Repeat("x", 200))
+	if len(long) > maxSocketPathLength {
+		t.Fatalf("socket path too long: %q", long)
+	}
+}
@@ -0,0 +1,56 @@
+//go:build !windows
+
+package wvapp
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+	"time"
+)
+
+// tryLockFile 以非阻塞方式获取排他锁，进程退出时由内核释放
+func tryLockFile(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		f.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, errLocked
+		}
+		return nil, err
+	}
+	return f, nil
+}
+
+// checkPrivateDir 确认目录不是符号链接、属于当前用户且权限为 0700
+func checkPrivateDir(dir string) error {
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+	if info.Mode()&os.ModeSymlink != 0 || !info.IsDir() {
+		return errors.New("not a directory")
+	}
+	if st, ok := info.Sys().(*syscall.Stat_t); ok && int(st.Uid) != os.Getuid() {
+		return fmt.Errorf("owned by uid %d", st.Uid)
+	}
+	if perm := info.Mode().Perm(); perm != 0o700 {
+		return fmt.Errorf("permissions %#o, want 0700", perm)
+	}
+	return nil
+}
+
+// listenSingleInstance 在 Unix domain socket 上监听，先删除残留的 socket 文件
+func listenSingleInstance(path string) (net.Listener, error) {
+	os.Remove(path)
+	return net.Listen("unix", path)
+}
+
+func dialSingleInstance(path string, timeout time.Duration) (net.Conn, error) {
+	return net.DialTimeout("unix", path, timeout)
+}
@@ -0,0 +1,55 @@
+//go:build !windows
+
+package wvapp
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestSingleInstanceDirRejectsForeignDir(t *testing.T) {
+	t.Setenv("XDG_RUNTIME_DIR", "")
+	tmp := t.TempDir()
+	t.Setenv("TMPDIR", tmp)
+	dir := filepath.Join(tmp, "wvapp-"+strconv.Itoa(os.Getuid()))
+
+	// 其他用户抢先创建的符号链接
+	target := t.TempDir()
+	if err := os.Symlink(target, dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := singleInstanceDir(); err == nil {
+		t.Fatal("a symlinked directory should be rejected")
+	}
+	os.Remove(dir)
+
+	if err := os.Mkdir(dir, 0o777); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(dir, 0o777); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := singleInstanceDir(); err == nil {
+		t.Fatal("a world-writable directory should be rejected")
+	}
+
+	if err := os.Chmod(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if os.Getuid() == 0 {
+		if err := os.Chown(dir, 65534, 65534); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := singleInstanceDir(); err == nil {
+			t.Fatal("a directory owned by another user should be rejected")
+		}
+		if err := os.Chown(dir, 0, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, err := singleInstanceDir(); err != nil || got != dir {
+		t.Fatalf("singleInstanceDir() = %q, %v", got, err)
+	}
+}
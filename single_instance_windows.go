@@ -0,0 +1,355 @@
+//go:build windows
+
+package wvapp
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+const (
+	errorSharingViolation syscall.Errno = 32
+	errorBrokenPipe       syscall.Errno = 109
+	errorPipeBusy         syscall.Errno = 231
+	errorPipeConnected    syscall.Errno = 535
+	errorOperationAborted syscall.Errno = 995
+
+	pipeAccessDuplex          = 0x00000003
+	pipeRejectRemoteClients   = 0x00000008
+	pipeUnlimitedInstances    = 255
+	pipeBufferSize            = 4096
+	fileFlagFirstPipeInstance = 0x00080000
+	securitySQOSPresent       = 0x00100000
+	securityIdentification    = 0x00010000 // 主实例只能识别而不能模拟连接的客户端
+	sddlRevision1             = 1
+	pipeNamePrefix            = `\\.\pipe\`
+)
+
+var (
+	modKernel32          = syscall.NewLazyDLL("kernel32.dll")
+	modAdvapi32          = syscall.NewLazyDLL("advapi32.dll")
+	procCreateNamedPipeW = modKernel32.NewProc("CreateNamedPipeW")
+	procConnectNamedPipe = modKernel32.NewProc("ConnectNamedPipe")
+	procWaitNamedPipeW   = modKernel32.NewProc("WaitNamedPipeW")
+	procCreateEventW     = modKernel32.NewProc("CreateEventW")
+	procGetOverlapped    = modKernel32.NewProc("GetOverlappedResult")
+	procConvertSDDL      = modAdvapi32.NewProc("ConvertStringSecurityDescriptorToSecurityDescriptorW")
+)
+
+// tryLockFile 以不共享的方式打开锁文件，其他进程无法同时打开，进程退出时句柄自动关闭
+func tryLockFile(path string) (*os.File, error) {
+	name, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, err
+	}
+	h, err := syscall.CreateFile(name, syscall.GENERIC_READ|syscall.GENERIC_WRITE, 0, nil,
+		syscall.OPEN_ALWAYS, syscall.FILE_ATTRIBUTE_NORMAL, 0)
+	if err != nil {
+		if errors.Is(err, errorSharingViolation) {
+			return nil, errLocked
+		}
+		return nil, err
+	}
+	return os.NewFile(uintptr(h), path), nil
+}
+
+// checkPrivateDir 目录位于当前用户的 LocalAppData 中，由系统 ACL 保护
+func checkPrivateDir(dir string) error {
+	return nil
+}
+
+// listenSingleInstance 创建只允许当前用户连接的命名管道，并把管道名写入 path。
+// 管道名随机生成，其他用户无法抢先创建同名管道冒充主实例
+func listenSingleInstance(path string) (net.Listener, error) {
+	var nonce [16]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return nil, err
+	}
+	sa, err := currentUserSecurityAttributes()
+	if err != nil {
+		return nil, err
+	}
+	l := &pipeListener{name: pipeNamePrefix + "wvapp-" + hex.EncodeToString(nonce[:]), sa: sa}
+	if l.next, err = l.createInstance(true); err != nil {
+		syscall.LocalFree(syscall.Handle(sa.SecurityDescriptor))
+		return nil, err
+	}
+	if err := os.WriteFile(path, []byte(l.name), 0o600); err != nil {
+		l.Close()
+		return nil, err
+	}
+	return l, nil
+}
+
+// dialSingleInstance 读取主实例写入的管道名并连接，管道忙时在 timeout 内等待
+func dialSingleInstance(path string, timeout time.Duration) (net.Conn, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	name := string(data)
+	if !strings.HasPrefix(name, pipeNamePrefix) {
+		return nil, fmt.Errorf("invalid pipe name %q", name)
+	}
+	namePtr, err := syscall.UTF16PtrFromString(name)
+	if err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		h, err := syscall.CreateFile(namePtr, syscall.GENERIC_READ|syscall.GENERIC_WRITE, 0, nil, syscall.OPEN_EXISTING,
+			syscall.FILE_FLAG_OVERLAPPED|securitySQOSPresent|securityIdentification, 0)
+		if err == nil {
+			return &pipeConn{h: h, name: name}, nil
+		}
+		remaining := time.Until(deadline).Milliseconds()
+		if !errors.Is(err, errorPipeBusy) || remaining <= 0 {
+			return nil, err
+		}
+		procWaitNamedPipeW.Call(uintptr(unsafe.Pointer(namePtr)), uintptr(remaining))
+	}
+}
+
+// currentUserSecurityAttributes 只授予当前用户访问权限的安全描述符，需要用 LocalFree 释放
+func currentUserSecurityAttributes() (*syscall.SecurityAttributes, error) {
+	token, err := syscall.OpenCurrentProcessToken()
+	if err != nil {
+		return nil, err
+	}
+	defer token.Close()
+	user, err := token.GetTokenUser()
+	if err != nil {
+		return nil, err
+	}
+	sid, err := user.User.Sid.String()
+	if err != nil {
+		return nil, err
+	}
+	sddl, err := syscall.UTF16PtrFromString("D:P(A;;GA;;;" + sid + ")")
+	if err != nil {
+		return nil, err
+	}
+	var sd uintptr
+	if r, _, err := procConvertSDDL.Call(uintptr(unsafe.Pointer(sddl)), sddlRevision1, uintptr(unsafe.Pointer(&sd)), 0); r == 0 {
+		return nil, err
+	}
+	return &syscall.SecurityAttributes{
+		Length:             uint32(unsafe.Sizeof(syscall.SecurityAttributes{})),
+		SecurityDescriptor: sd,
+	}, nil
+}
+
+// pipeAddr 命名管道地址
+type pipeAddr string
+
+func (a pipeAddr) Network() string { return "pipe" }
+func (a pipeAddr) String() string  { return string(a) }
+
+// pipeListener 基于命名管道的 net.Listener，始终预留一个等待连接的管道实例
+type pipeListener struct {
+	name    string
+	sa      *syscall.SecurityAttributes
+	mu      sync.Mutex
+	next    syscall.Handle // 尚未开始等待连接的实例
+	pending syscall.Handle // Accept 正在等待连接的实例
+	closed  bool
+}
+
+func (l *pipeListener) createInstance(first bool) (syscall.Handle, error) {
+	name, err := syscall.UTF16PtrFromString(l.name)
+	if err != nil {
+		return 0, err
+	}
+	mode := uint32(pipeAccessDuplex | syscall.FILE_FLAG_OVERLAPPED)
+	if first {
+		mode |= fileFlagFirstPipeInstance
+	}
+	h, _, err := procCreateNamedPipeW.Call(uintptr(unsafe.Pointer(name)), uintptr(mode), pipeRejectRemoteClients,
+		pipeUnlimitedInstances, pipeBufferSize, pipeBufferSize, 0, uintptr(unsafe.Pointer(l.sa)))
+	if syscall.Handle(h) == syscall.InvalidHandle {
+		return 0, err
+	}
+	return syscall.Handle(h), nil
+}
+
+func (l *pipeListener) Accept() (net.Conn, error) {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil, net.ErrClosed
+	}
+	h := l.next
+	l.next = 0
+	if h == 0 {
+		var err error
+		if h, err = l.createInstance(false); err != nil {
+			l.mu.Unlock()
+			return nil, err
+		}
+	}
+	l.pending = h
+	l.mu.Unlock()
+
+	err := connectPipe(h)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.pending = 0
+	if l.closed || err != nil {
+		syscall.CloseHandle(h)
+		if l.closed || errors.Is(err, errorOperationAborted) {
+			return nil, net.ErrClosed
+		}
+		return nil, err
+	}
+	// 立即准备下一个实例，避免其他实例在两次 Accept 之间连接失败
+	if next, err := l.createInstance(false); err == nil {
+		l.next = next
+	}
+	return &pipeConn{h: h, name: l.name}, nil
+}
+
+func (l *pipeListener) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return nil
+	}
+	l.closed = true
+	if l.next != 0 {
+		syscall.CloseHandle(l.next)
+		l.next = 0
+	}
+	if l.pending != 0 {
+		syscall.CancelIoEx(l.pending, nil)
+	}
+	syscall.LocalFree(syscall.Handle(l.sa.SecurityDescriptor))
+	return nil
+}
+
+func (l *pipeListener) Addr() net.Addr { return pipeAddr(l.name) }
+
+// connectPipe 等待客户端连接到管道实例
+func connectPipe(h syscall.Handle) error {
+	ov, err := newOverlapped()
+	if err != nil {
+		return err
+	}
+	defer syscall.CloseHandle(ov.HEvent)
+	if r, _, err := procConnectNamedPipe.Call(uintptr(h), uintptr(unsafe.Pointer(ov))); r == 0 {
+		switch {
+		case errors.Is(err, errorPipeConnected):
+		case errors.Is(err, syscall.ERROR_IO_PENDING):
+			_, err := overlappedResult(h, ov)
+			return err
+		default:
+			return err
+		}
+	}
+	return nil
+}
+
+// newOverlapped 使用手动重置事件的 OVERLAPPED，事件句柄由调用方关闭
+func newOverlapped() (*syscall.Overlapped, error) {
+	ev, _, err := procCreateEventW.Call(0, 1, 0, 0)
+	if ev == 0 {
+		return nil, err
+	}
+	return &syscall.Overlapped{HEvent: syscall.Handle(ev)}, nil
+}
+
+// overlappedResult 等待重叠 I/O 完成并返回传输的字节数
+func overlappedResult(h syscall.Handle, ov *syscall.Overlapped) (uint32, error) {
+	var n uint32
+	if r, _, err := procGetOverlapped.Call(uintptr(h), uintptr(unsafe.Pointer(ov)), uintptr(unsafe.Pointer(&n)), 1); r == 0 {
+		return n, err
+	}
+	return n, nil
+}
+
+// pipeConn 命名管道连接，读写共用 SetDeadline 设置的时限
+type pipeConn struct {
+	h         syscall.Handle
+	name      string
+	mu        sync.Mutex
+	deadline  time.Time
+	closeOnce sync.Once
+}
+
+type pipeIO func(syscall.Handle, []byte, *uint32, *syscall.Overlapped) error
+
+func (c *pipeConn) Read(b []byte) (int, error) {
+	n, err := c.do(b, syscall.ReadFile)
+	if errors.Is(err, errorBrokenPipe) {
+		return n, io.EOF
+	}
+	return n, err
+}
+
+func (c *pipeConn) Write(b []byte) (int, error) {
+	return c.do(b, syscall.WriteFile)
+}
+
+// do 发起重叠 I/O 并等待完成，超过时限时取消操作
+func (c *pipeConn) do(b []byte, op pipeIO) (int, error) {
+	c.mu.Lock()
+	deadline := c.deadline
+	c.mu.Unlock()
+	timeout := uint32(syscall.INFINITE)
+	if !deadline.IsZero() {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return 0, os.ErrDeadlineExceeded
+		}
+		timeout = uint32(remaining.Milliseconds())
+	}
+
+	ov, err := newOverlapped()
+	if err != nil {
+		return 0, err
+	}
+	defer syscall.CloseHandle(ov.HEvent)
+	var n uint32
+	err = op(c.h, b, &n, ov)
+	if !errors.Is(err, syscall.ERROR_IO_PENDING) {
+		return int(n), err
+	}
+	timedOut := false
+	if ev, _ := syscall.WaitForSingleObject(ov.HEvent, timeout); ev == syscall.WAIT_TIMEOUT {
+		syscall.CancelIoEx(c.h, ov)
+		timedOut = true
+	}
+	n, err = overlappedResult(c.h, ov)
+	if timedOut && errors.Is(err, errorOperationAborted) {
+		return int(n), os.ErrDeadlineExceeded
+	}
+	return int(n), err
+}
+
+func (c *pipeConn) Close() error {
+	var err error
+	c.closeOnce.Do(func() { err = syscall.CloseHandle(c.h) })
+	return err
+}
+
+func (c *pipeConn) SetDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.deadline = t
+	return nil
+}
+
+func (c *pipeConn) SetReadDeadline(t time.Time) error  { return c.SetDeadline(t) }
+func (c *pipeConn) SetWriteDeadline(t time.Time) error { return c.SetDeadline(t) }
+func (c *pipeConn) LocalAddr() net.Addr                { return pipeAddr(c.name) }
+func (c *pipeConn) RemoteAddr() net.Addr               { return pipeAddr(c.name) }
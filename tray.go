@@ -0,0 +1,225 @@
+package wvapp
+
+import (
+	"fmt"
+	"log/slog"
+	"runtime"
+	"sync"
+
+	"github.com/ebitengine/purego"
+)
+
+// TrayEvent 托盘图标事件
+type TrayEvent int32
+
+const (
+	TrayClick TrayEvent = iota
+	TrayDoubleClick
+)
+
+// TrayOptions 托盘图标选项
+type TrayOptions struct {
+	Icon          []byte // PNG 或 ICO 数据，与 WindowOptions.Icon 相同
+	Tooltip       string
+	Menu          *Menu  // 右键（macOS 上为单击）弹出的菜单，点击回调中 MenuClickEvent.Window 为 nil
+	OnClick       func() // 在独立 goroutine 中调用
+	OnDoubleClick func()
+}
+
+// Tray 系统托盘（macOS 状态栏）图标
+type Tray struct {
+	id   uintptr
+	mu   sync.Mutex
+	opts TrayOptions
+}
+
+var (
+	trayRegistry     = make(map[uintptr]*Tray)
+	trayMutex        sync.Mutex
+	trayNextID       uintptr
+	trayCallback     uintptr
+	trayCallbackOnce sync.Once
+)
+
+// NewTray 创建托盘图标。通常与 SetKeepAlive(true) 配合，使窗口全部关闭后程序仍在托盘中运行
+func NewTray(opts TrayOptions) (*Tray, error) {
+	if err := validateIcon(opts.Icon); err != nil {
+		return nil, err
+	}
+	if err := loadWebviewLibrary(); err != nil {
+		return nil, err
+	}
+	if webviewTrayCreate == nil || webviewSetTrayCallback == nil {
+		return nil, ErrNotSupported
+	}
+	if opts.Menu != nil && webviewTraySetMenu == nil {
+		return nil, ErrNotSupported
+	}
+	trayCallbackOnce.Do(func() {
+		trayCallback = purego.NewCallback(cTrayHandler)
+		mainScheduler.RunInMainThread(func() { webviewSetTrayCallback(trayCallback) })
+	})
+
+	trayMutex.Lock()
+	trayNextID++
+	t := &Tray{id: trayNextID, opts: opts}
+	trayRegistry[t.id] = t
+	trayMutex.Unlock()
+
+	menuJSON, err := mountNativeMenu(trayMenuSlot{t.id}, opts.Menu)
+	if err != nil {
+		trayMutex.Lock()
+		delete(trayRegistry, t.id)
+		trayMutex.Unlock()
+		return nil, err
+	}
+	if menuJSON != nil {
+		installMenuCallback()
+	}
+
+	ok := mainScheduler.RunInMainThreadWithResult(func() any {
+		iconPtr, iconLen := iconPointer(opts.Icon)
+		cstr, tooltipPtr := cString(opts.Tooltip)
+		created := webviewTrayCreate(t.id, iconPtr, iconLen, tooltipPtr)
+		runtime.KeepAlive(opts.Icon)
+		runtime.KeepAlive(cstr)
+		if created && menuJSON != nil {
+			mcstr, menuPtr := cString(string(menuJSON))
+			webviewTraySetMenu(t.id, menuPtr)
+			runtime.KeepAlive(mcstr)
+		}
+		return created
+	}).(bool)
+	if !ok {
+		trayMutex.Lock()
+		delete(trayRegistry, t.id)
+		trayMutex.Unlock()
+		_, _ = mountNativeMenu(trayMenuSlot{t.id}, nil)
+		return nil, fmt.Errorf("webview: failed to create tray icon")
+	}
+	return t, nil
+}
+
+// SetIcon 更新托盘图标，例如显示同步进度
+func (t *Tray) SetIcon(icon []byte) error {
+	if err := validateIcon(icon); err != nil {
+		return err
+	}
+	if webviewTraySetIcon == nil {
+		return ErrNotSupported
+	}
+	icon = append([]byte(nil), icon...)
+	t.mu.Lock()
+	t.opts.Icon = icon
+	t.mu.Unlock()
+	mainScheduler.RunInMainThread(func() {
+		iconPtr, iconLen := iconPointer(icon)
+		webviewTraySetIcon(t.id, iconPtr, iconLen)
+		runtime.KeepAlive(icon)
+	})
+	return nil
+}
+
+// SetTooltip 更新鼠标悬停提示
+func (t *Tray) SetTooltip(tooltip string) error {
+	if webviewTraySetTooltip == nil {
+		return ErrNotSupported
+	}
+	t.mu.Lock()
+	t.opts.Tooltip = tooltip
+	t.mu.Unlock()
+	mainScheduler.RunInMainThread(func() {
+		cstr, ptr := cString(tooltip)
+		webviewTraySetTooltip(t.id, ptr)
+		runtime.KeepAlive(cstr)
+	})
+	return nil
+}
+
+// SetMenu 替换托盘菜单，nil 表示移除
+func (t *Tray) SetMenu(menu *Menu) error {
+	if webviewTraySetMenu == nil {
+		return ErrNotSupported
+	}
+	data, err := mountNativeMenu(trayMenuSlot{t.id}, menu)
+	if err != nil {
+		return err
+	}
+	if data != nil {
+		installMenuCallback()
+	}
+	t.mu.Lock()
+	t.opts.Menu = menu
+	t.mu.Unlock()
+	mainScheduler.RunInMainThread(func() {
+		if data == nil {
+			webviewTraySetMenu(t.id, 0)
+			return
+		}
+		cstr, ptr := cString(string(data))
+		webviewTraySetMenu(t.id, ptr)
+		runtime.KeepAlive(cstr)
+	})
+	return nil
+}
+
+// OnClick 设置单击回调
+func (t *Tray) OnClick(fn func()) {
+	t.mu.Lock()
+	t.opts.OnClick = fn
+	t.mu.Unlock()
+}
+
+// OnDoubleClick 设置双击回调
+func (t *Tray) OnDoubleClick(fn func()) {
+	t.mu.Lock()
+	t.opts.OnDoubleClick = fn
+	t.mu.Unlock()
+}
+
+// Destroy 移除托盘图标，之后对该 Tray 的调用不再生效
+func (t *Tray) Destroy() {
+	trayMutex.Lock()
+	_, ok := trayRegistry[t.id]
+	delete(trayRegistry, t.id)
+	trayMutex.Unlock()
+	_, _ = mountNativeMenu(trayMenuSlot{t.id}, nil)
+	if ok && webviewTrayDestroy != nil {
+		mainScheduler.RunInMainThread(func() { webviewTrayDestroy(t.id) })
+	}
+}
+
+// handleTrayEvent 分发托盘事件，回调在独立 goroutine 中执行
+func handleTrayEvent(id uintptr, event TrayEvent) {
+	trayMutex.Lock()
+	t := trayRegistry[id]
+	trayMutex.Unlock()
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	var fn func()
+	switch event {
+	case TrayClick:
+		fn = t.opts.OnClick
+	case TrayDoubleClick:
+		fn = t.opts.OnDoubleClick
+	}
+	t.mu.Unlock()
+	if fn == nil {
+		return
+	}
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				slog.Error("Panic in tray handler", "event", event, "panic", r)
+			}
+		}()
+		fn()
+	}()
+}
+
+func cTrayHandler(id uintptr, event int32) uintptr {
+	handleTrayEvent(id, TrayEvent(event))
+	return 0
+}
@@ -0,0 +1,84 @@
+package wvapp
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidateIcon(t *testing.T) {
+	png := append([]byte("\x89PNG\r\n\x1a\n"), 0, 0, 0, 13)
+	ico := []byte{0, 0, 1, 0, 1, 0}
+	for _, icon := range [][]byte{nil, png, ico} {
+		if err := validateIcon(icon); err != nil {
+			t.Fatalf("validateIcon(% x): %v", icon, err)
+		}
+	}
+	if err := validateIcon([]byte("GIF89a")); err == nil {
+		t.Fatal("GIF data should be rejected")
+	}
+	if ptr, n := iconPointer(nil); ptr != 0 || n != 0 {
+		t.Fatalf("iconPointer(nil) = %d, %d", ptr, n)
+	}
+	if _, n := iconPointer(png); n != uint64(len(png)) {
+		t.Fatalf("iconPointer length = %d", n)
+	}
+}
+
+// registerTestTray 不经过原生库注册托盘，用于测试事件分发
+func registerTestTray(opts TrayOptions) *Tray {
+	trayMutex.Lock()
+	defer trayMutex.Unlock()
+	trayNextID++
+	tray := &Tray{id: trayNextID, opts: opts}
+	trayRegistry[tray.id] = tray
+	return tray
+}
+
+func TestTrayEvents(t *testing.T) {
+	clicks := make(chan string, 2)
+	tray := registerTestTray(TrayOptions{OnClick: func() { clicks <- "click" }})
+	tray.OnDoubleClick(func() { clicks <- "double" })
+
+	cTrayHandler(tray.id, int32(TrayClick))
+	cTrayHandler(tray.id, int32(TrayDoubleClick))
+	got := map[string]bool{}
+	for range 2 {
+		select {
+		case c := <-clicks:
+			got[c] = true
+		case <-time.After(time.Second):
+			t.Fatal("tray handler not called")
+		}
+	}
+	if !got["click"] || !got["double"] {
+		t.Fatalf("events = %v", got)
+	}
+
+	tray.Destroy()
+	cTrayHandler(tray.id, int32(TrayClick))
+	select {
+	case c := <-clicks:
+		t.Fatalf("destroyed tray still dispatched %q", c)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestShouldExitLoop(t *testing.T) {
+	defer SetKeepAlive(false)
+	defer quitRequested.Store(false)
+
+	if shouldExitLoop(false) {
+		t.Fatal("loop should keep running while the native side is busy")
+	}
+	if !shouldExitLoop(true) {
+		t.Fatal("loop should exit when the last window closes")
+	}
+	SetKeepAlive(true)
+	if shouldExitLoop(true) {
+		t.Fatal("keep-alive should keep the loop running without windows")
+	}
+	quitRequested.Store(true)
+	if !shouldExitLoop(false) {
+		t.Fatal("Quit should stop the loop even with keep-alive")
+	}
+}
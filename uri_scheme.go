@@ -6,7 +6,7 @@ import (
 	"io/fs"
 	"log/slog"
 	"mime"
-	"path/filepath"
+	"path"
 	"runtime"
 	"strings"
 	"sync"
@@ -33,6 +33,7 @@ type cWebviewResource struct {
 
 var (
 	globalResourceHandler atomic.Value
+	registeredScheme      atomic.Value // 当前注册的 scheme 名称，导航策略默认放行
 
 	uriSchemeLoadOnce sync.Once
 	uriSchemeInitErr  error
@@ -124,42 +125,60 @@ func ensureLibraryLoaded() error {
 	return nil
 }
 
-// NewResourceHandlerFromFS 从文件系统创建资源处理函数
+// NewResourceHandlerFromFS 从文件系统创建资源处理函数（使用 DefaultResourcePolicy）
 func NewResourceHandlerFromFS(fsys fs.FS) ResourceHandler {
+	return NewResourceHandlerFromFSWithPolicy(fsys, DefaultResourcePolicy())
+}
+
+// NewResourceHandlerFromFSWithPolicy 从文件系统创建资源处理函数，并按策略限制可访问的文件
+func NewResourceHandlerFromFSWithPolicy(fsys fs.FS, policy ResourcePolicy) ResourceHandler {
 	if fsys == nil {
 		return func(path string) *Resource {
 			slog.Error("File system is nil")
 			return nil
 		}
 	}
-	return func(path string) *Resource {
-		path = normalizePath(filepath.Clean(path))
+	return func(rawPath string) *Resource {
 		defer func() {
 			if r := recover(); r != nil {
-				slog.Error("Panic in resource handler from FS", "error", r, "path", path)
+				slog.Error("Panic in resource handler from FS", "error", r, "path", rawPath)
 			}
 		}()
-		f, err := fsys.Open(path)
+		name, err := policy.Resolve(rawPath)
+		if err != nil {
+			slog.Warn("Resource request rejected", "path", rawPath, "error", err)
+			return nil
+		}
+		f, err := fsys.Open(name)
 		if err != nil {
-			slog.Warn("Resource not found in FS", "path", path, "error", err)
+			slog.Warn("Resource not found in FS", "path", name, "error", err)
 			return nil
 		}
 		defer f.Close()
+		if fi, err := f.Stat(); err != nil || fi.IsDir() {
+			return nil
+		}
 		data, err := io.ReadAll(f)
 		if err != nil || len(data) == 0 {
 			return nil
 		}
-		mimeType := mime.TypeByExtension(filepath.Ext(path))
-		if mimeType == "" {
-			mimeType = "application/octet-stream"
-		}
 		return &Resource{
 			Content:     data,
-			ContentType: mimeType,
+			ContentType: resourceMimeType(name),
 			IsEmbed:     false,
 		}
 	}
 }
+
+func resourceMimeType(name string) string {
+	mimeType := mime.TypeByExtension(path.Ext(name))
+	if mimeType == "" {
+		//TODO: 从body中推断MIME类型 github.com/gabriel-vasile/mimetype
+		mimeType = "application/octet-stream"
+	}
+	return mimeType
+}
+
 func normalizePath(path string) string {
 	const prefix = "index.html/"
 	for len(path) > 0 && path[0] == '/' {
@@ -174,23 +193,27 @@ func normalizePath(path string) string {
 	return path
 }
 
-// NewResourceHandlerFromStaticCache 从静态缓存创建资源处理函数
+// NewResourceHandlerFromStaticCache 从静态缓存创建资源处理函数（使用 DefaultResourcePolicy）
 func NewResourceHandlerFromStaticCache(staticCache map[string][]byte) ResourceHandler {
-	return func(path string) *Resource {
-		path = normalizePath(filepath.Clean(path))
+	return NewResourceHandlerFromStaticCacheWithPolicy(staticCache, DefaultResourcePolicy())
+}
 
-		data, ok := staticCache[path]
-		if !ok {
+// NewResourceHandlerFromStaticCacheWithPolicy 从静态缓存创建资源处理函数，并按策略限制可访问的文件
+func NewResourceHandlerFromStaticCacheWithPolicy(staticCache map[string][]byte, policy ResourcePolicy) ResourceHandler {
+	return func(rawPath string) *Resource {
+		name, err := policy.Resolve(rawPath)
+		if err != nil {
+			slog.Warn("Resource request rejected", "path", rawPath, "error", err)
 			return nil
 		}
-		mimeType := mime.TypeByExtension(filepath.Ext(path))
-		if mimeType == "" {
-			//TODO: 从body中推断MIME类型 github.com/gabriel-vasile/mimetype
-			mimeType = "application/octet-stream"
+
+		data, ok := staticCache[name]
+		if !ok {
+			return nil
 		}
 		return &Resource{
 			Content:     data,
-			ContentType: mimeType,
+			ContentType: resourceMimeType(name),
 			IsEmbed:     true,
 		}
 	}
@@ -235,6 +258,7 @@ func RegisterGlobalURIScheme(schemeName string, handler ResourceHandler) error {
 		globalResourceHandler.Store(nilHandler)
 		return fmt.Errorf("failed to register URI scheme '%s': error code %d", schemeName, result)
 	}
+	registeredScheme.Store(schemeName)
 
 	return nil
 }
@@ -277,6 +301,7 @@ func CleanupGlobalURIScheme() error {
 
 	var nilHandler ResourceHandler
 	globalResourceHandler.Store(nilHandler)
+	registeredScheme.Store("")
 
 	if mainScheduler != nil {
 		mainScheduler.RunInMainThread(func() {
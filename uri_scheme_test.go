@@ -0,0 +1,156 @@
+package wvapp
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"index.html":           {Data: []byte("<html></html>")},
+		"assets/app.js":        {Data: []byte("console.log(1)")},
+		"assets/app.js.map":    {Data: []byte("{}")},
+		"assets/sub/style.css": {Data: []byte("body{}")},
+		"docs/index.html":      {Data: []byte("docs")},
+		".env":                 {Data: []byte("SECRET=1")},
+		".git/config":          {Data: []byte("[core]")},
+		"private/key.pem":      {Data: []byte("key")},
+		"a b.txt":              {Data: []byte("space")},
+	}
+}
+
+func TestResourceHandlerFromFSServesFiles(t *testing.T) {
+	h := NewResourceHandlerFromFS(testFS())
+	cases := map[string]string{
+		"/":                     "<html></html>",
+		"":                      "<html></html>",
+		"/index.html":           "<html></html>",
+		"/index.html/":          "<html></html>",
+		"/assets/app.js":        "console.log(1)",
+		"assets//sub/style.css": "body{}",
+		"/assets/./app.js":      "console.log(1)",
+		"/assets/app.js?v=1":    "console.log(1)",
+		"/assets/app.js#frag":   "console.log(1)",
+		"/docs/":                "docs",
+		"/a%20b.txt":            "space",
+	}
+	for in, want := range cases {
+		res := h(in)
+		if res == nil {
+			t.Errorf("%q: expected resource, got nil", in)
+			continue
+		}
+		if string(res.Content) != want {
+			t.Errorf("%q: got %q, want %q", in, res.Content, want)
+		}
+	}
+	if res := h("/assets/app.js"); res != nil && res.ContentType != "text/javascript; charset=utf-8" {
+		t.Errorf("unexpected content type %q", res.ContentType)
+	}
+}
+
+func TestResourceHandlerFromFSRejectsTraversal(t *testing.T) {
+	h := NewResourceHandlerFromFS(testFS())
+	rejected := []string{
+		"../index.html",
+		"/../index.html",
+		"/assets/../../index.html",
+		"/assets/..",
+		"..\\index.html",
+		"/assets\\app.js",
+		"/%2e%2e/index.html",
+		"/%2E%2E%2Findex.html",
+		"/assets%2fapp.js",
+		"/assets%2Fapp.js",
+		"/assets%5capp.js",
+		"/index.html\x00.png",
+		"/index.html%00.png",
+		"/%zz",
+		"/" + strings.Repeat("a/", DefaultMaxResourcePathLength),
+		"/assets", // 目录本身不能作为资源返回
+	}
+	for _, in := range rejected {
+		if res := h(in); res != nil {
+			t.Errorf("%q: expected rejection, got %q", in, res.Content)
+		}
+	}
+}
+
+func TestResourceHandlerFromFSHiddenAndSourceMaps(t *testing.T) {
+	h := NewResourceHandlerFromFS(testFS())
+	for _, in := range []string{"/.env", "/.git/config", "/assets/app.js.map", "/%2eenv"} {
+		if res := h(in); res != nil {
+			t.Errorf("%q: expected rejection by default policy", in)
+		}
+	}
+
+	h = NewResourceHandlerFromFSWithPolicy(testFS(), ResourcePolicy{AllowHidden: true})
+	for _, in := range []string{"/.env", "/assets/app.js.map"} {
+		if res := h(in); res == nil {
+			t.Errorf("%q: expected resource with permissive policy", in)
+		}
+	}
+}
+
+func TestResourcePolicyAllowDeny(t *testing.T) {
+	policy := ResourcePolicy{
+		Allow: []string{"/index.html", "assets/**"},
+		Deny:  []string{"**/*.css"},
+	}
+	h := NewResourceHandlerFromFSWithPolicy(testFS(), policy)
+	if h("/") == nil || h("/assets/app.js") == nil || h("/assets/app.js.map") == nil {
+		t.Fatal("expected allowed resources to be served")
+	}
+	for _, in := range []string{"/private/key.pem", "/docs/", "/assets/sub/style.css"} {
+		if res := h(in); res != nil {
+			t.Errorf("%q: expected rejection", in)
+		}
+	}
+}
+
+func TestMatchResourcePattern(t *testing.T) {
+	cases := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"*.map", "a/b/c.js.map", true},
+		{"*.map", "c.js", false},
+		{"assets/*", "assets/app.js", true},
+		{"assets/*", "assets/sub/style.css", false},
+		{"assets/**", "assets/sub/style.css", true},
+		{"/assets/**", "assets/app.js", true},
+		{"**/style.css", "assets/sub/style.css", true},
+		{"**/style.css", "style.css", true},
+		{"assets/**/x", "assets/x", true},
+		{"/index.html", "docs/index.html", false},
+		{"index.html", "docs/index.html", true},
+		{"[", "anything", false},
+	}
+	for _, c := range cases {
+		if got := matchResourcePattern(c.pattern, c.name); got != c.want {
+			t.Errorf("matchResourcePattern(%q, %q) = %v, want %v", c.pattern, c.name, got, c.want)
+		}
+	}
+}
+
+func TestResourceHandlerFromStaticCacheUsesPolicy(t *testing.T) {
+	cache := map[string][]byte{
+		"index.html":    []byte("home"),
+		"app.js.map":    []byte("{}"),
+		".hidden":       []byte("x"),
+		"assets/app.js": []byte("js"),
+	}
+	h := NewResourceHandlerFromStaticCache(cache)
+	if res := h("/"); res == nil || string(res.Content) != "home" {
+		t.Fatal("expected index.html")
+	}
+	if res := h("/assets/app.js"); res == nil || !res.IsEmbed {
+		t.Fatal("expected embedded assets/app.js")
+	}
+	for _, in := range []string{"/app.js.map", "/.hidden", "/../index.html", "/assets%2fapp.js"} {
+		if res := h(in); res != nil {
+			t.Errorf("%q: expected rejection", in)
+		}
+	}
+}
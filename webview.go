@@ -2,7 +2,9 @@ package wvapp
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"runtime"
 	"sync"
@@ -16,6 +18,7 @@ type Webview struct{}
 
 var (
 	loadOnce                 sync.Once
+	libraryInitErr           error
 	webviewCreate            func(*cWebviewWindowOptions) *Webview
 	webviewSetUrl            func(*Webview, uintptr)
 	webviewSetHtml           func(*Webview, uintptr)
@@ -35,8 +38,58 @@ var (
 	webviewMaximize          func(*Webview)
 	webviewMinimize          func(*Webview)
 	webviewRestore           func(*Webview)
+
+	webviewSetNavigationCallback    func(*Webview, uintptr)
+	webviewSetContentSecurityPolicy func(*Webview, uintptr)
+	webviewSetEventDataCallback     func(*Webview, uintptr)
+	webviewAddInitScript            func(*Webview, uintptr)
+	webviewClearInitScripts         func(*Webview)
+	webviewGetWindowState           func(*Webview) uintptr // 返回 JSON，需要用 webviewFreeString 释放
+	webviewFreeString               func(uintptr)
+	webviewGetScreens               func() uintptr // 返回 JSON 数组，需要用 webviewFreeString 释放
+	webviewSetPosition              func(*Webview, int, int)
+	webviewFocus                    func(*Webview)
+	webviewBringToFront             func(*Webview)
+	webviewSetParent                func(*Webview, *Webview, bool)
+	webviewSetAlwaysOnTop           func(*Webview, bool)
+	webviewSetSkipTaskbar           func(*Webview, bool)
+	webviewFileDialog               func(*Webview, int32, uintptr) uintptr           // 返回 JSON 路径数组，取消时返回 0
+	webviewFileDialogAsync          func(*Webview, int32, uintptr, uintptr, uintptr) // 立即返回，结果通过回调通知
+	webviewMessageDialog            func(*Webview, uintptr, uintptr, uintptr)        // 立即返回，用户选择后通过回调通知
+	webviewSetMenu                  func(*Webview, uintptr)                          // 窗口为 nil 时设置应用菜单，JSON 为 0 时移除
+	webviewSetMenuCallback          func(uintptr)
+	webviewUpdateMenuItem           func(uintptr, uintptr)
+	webviewShowContextMenu          func(*Webview, uintptr, int, int)
+	webviewTrayCreate               func(uintptr, uintptr, uint64, uintptr) bool // 托盘 ID 由 Go 端分配
+	webviewTraySetIcon              func(uintptr, uintptr, uint64)
+	webviewTraySetTooltip           func(uintptr, uintptr)
+	webviewTraySetMenu              func(uintptr, uintptr)
+	webviewTrayDestroy              func(uintptr)
+	webviewSetTrayCallback          func(uintptr)
+	webviewClipboardRead            func(int32, *uint64) *byte // 返回的数据需要用 webviewFreeString 释放，没有数据时返回 nil
+	webviewClipboardWrite           func(int32, uintptr, uint64) bool
+	webviewNotify                   func(uintptr, uintptr, uintptr) bool // 通知 JSON、回调、回调 ID
+	webviewSetFileDropOptions       func(*Webview, bool)
+	webviewSetOpenURLCallback       func(uintptr)                                    // 系统通过事件而不是命令行传递 URL 时使用（macOS）
+	webviewSetDataStore             func(uintptr, bool)                              // 设置下一个创建的窗口使用的数据目录或内存存储
+	webviewDataRequest              func(*Webview, int32, uintptr, uintptr, uintptr) // 操作、参数 JSON、回调、回调 ID
+	webviewSetDownloadCallback      func(*Webview, uintptr)
+	webviewDownloadSetDestination   func(*Webview, uintptr, uintptr) // 只能在下载回调中调用
+	webviewDownloadCancel           func(*Webview, uintptr)
+	webviewSetPermissionCallback    func(*Webview, uintptr)
+	webviewPermissionRespond        func(*Webview, uintptr, bool) // 回答回调中返回 0 的请求
+	webviewSetHeadless              func(bool)                    // 设置下一个创建的窗口是否离屏（不显示）
+	webviewPrint                    func(*Webview, uintptr)
+	webviewPrintToPDF               func(*Webview, uintptr, uintptr, uintptr) // 选项 JSON、回调、回调 ID
+	webviewCapture                  func(*Webview, uintptr, uintptr, uintptr) // 区域 JSON、回调、回调 ID，回调返回 PNG
+	webviewApplySettings            func(*Webview, uintptr) uintptr           // 成功返回 0，否则返回错误描述，需要用 webviewFreeString 释放
+	webviewBridgeCaller             func(*Webview) uintptr                    // 当前绑定调用的来源 JSON，只在绑定回调中有效，需要用 webviewFreeString 释放
+	webviewSetHardwareAcceleration  func(uintptr)                             // 设置下一个创建的窗口的硬件加速策略，0 恢复默认
 )
 
+// ErrNotSupported 表示当前加载的原生库不支持该功能
+var ErrNotSupported = errors.New("webview: not supported by the native library")
+
 func NewWebview(options *WindowOptions) (*Webview, error) {
 	if options == nil {
 		options = &WindowOptions{
@@ -56,12 +109,173 @@ func NewWebview(options *WindowOptions) (*Webview, error) {
 			DisableResize: false,
 		}
 	}
-	var initErr error
+	if err := loadWebviewLibrary(); err != nil {
+		return nil, err
+	}
+	dataDir, err := dataStoreOptions(options)
+	if err != nil {
+		return nil, err
+	}
+	customDataStore := dataDir != "" || options.Ephemeral
+	if customDataStore && webviewSetDataStore == nil {
+		return nil, fmt.Errorf("webview: DataDir/Ephemeral: %w", ErrNotSupported)
+	}
+	if options.Headless && webviewSetHeadless == nil {
+		return nil, fmt.Errorf("webview: Headless: %w", ErrNotSupported)
+	}
+	if err := options.WebSettings.validate(); err != nil {
+		return nil, err
+	}
+	if err := options.WebSettings.checkSupported(); err != nil {
+		return nil, err
+	}
+	hardware, webSettings := options.WebSettings.splitCreation()
+
+	var stateStore *WindowStateStore
+	var savedState SavedWindowState
+	var restoreState bool
+	if options.StateKey != "" {
+		if stateStore = defaultWindowStateStore(); stateStore != nil {
+			saved, ok, err := stateStore.Load(options.StateKey)
+			if err != nil {
+				slog.Warn("Failed to load window state", "key", options.StateKey, "error", err)
+			} else if ok {
+				savedState, restoreState = saved, true
+				options = restoreWindowOptions(options, saved)
+			}
+		}
+	}
+	atomic.AddInt32(&windowCount, 1)
+
+	var titlePtr, iconPtr uintptr
+	var iconLen uint64
+	var titleBytes, iconBytes []byte
+	if options.Title != "" {
+		titleBytes = append([]byte(options.Title), 0)
+		titlePtr = uintptr(unsafe.Pointer(&titleBytes[0]))
+	}
+	iconBytes = options.Icon
+	iconPtr, iconLen = iconPointer(iconBytes)
+
+	cOptions := newCWindowOptions(options, titlePtr, iconPtr, iconLen)
+
+	wv := mainScheduler.RunInMainThreadWithResult(func() any {
+		if !customDataStore && !options.Headless && hardware == HardwareAccelerationDefault {
+			return webviewCreate(cOptions)
+		}
+		// 以下设置只作用于下一次创建，之后的窗口恢复默认
+		if hardware != HardwareAccelerationDefault {
+			policyBytes, policyPtr := cString(string(hardware))
+			webviewSetHardwareAcceleration(policyPtr)
+			defer webviewSetHardwareAcceleration(0)
+			defer runtime.KeepAlive(policyBytes)
+		}
+		if options.Headless {
+			webviewSetHeadless(true)
+			defer webviewSetHeadless(false)
+		}
+		if customDataStore {
+			dirBytes, dirPtr := cString(dataDir)
+			webviewSetDataStore(dirPtr, options.Ephemeral)
+			defer webviewSetDataStore(0, false)
+			defer runtime.KeepAlive(dirBytes)
+		}
+		return webviewCreate(cOptions)
+	}).(*Webview)
+
+	runtime.KeepAlive(titleBytes)
+	runtime.KeepAlive(iconBytes)
+
+	if wv == nil {
+		atomic.AddInt32(&windowCount, -1)
+		return nil, fmt.Errorf("webview: failed to create webview instance")
+	}
+
+	id := registerWindow(wv, options.Name)
+	// fail 关闭已创建的窗口并返回错误，用于必须生效的选项
+	fail := func(err error) (*Webview, error) {
+		wv.Terminate()
+		atomic.AddInt32(&windowCount, -1)
+		releaseWindow(wv)
+		return nil, err
+	}
+	// 在加载任何内容之前应用，WebSettings 中的字段优先于 Enable* 选项；
+	// 无法应用时关闭窗口，避免例如 JavaScript: Bool(false) 被静默忽略
+	if err := wv.SetWebSettings(webSettings); err != nil {
+		return fail(err)
+	}
+	wv.SetBridgeOriginPolicy(options.BridgePolicy)
+	wv.trackBridgeOrigin()
+	// 导航白名单与 CSP 是安全限制，无法生效时不能打开一个不受限制的窗口
+	if err := wv.applyNavigationOptions(options); err != nil {
+		return fail(err)
+	}
+	if err := wv.applyRelationOptions(options); err != nil {
+		return fail(err)
+	}
+	if options.Menu != nil {
+		if err := wv.SetMenu(options.Menu); err != nil {
+			slog.Warn("Window menu ignored", "error", err)
+		}
+	}
+	wv.SetEventCallback(nil)
+	wv.installEventDataCallback()
+	wv.installFileDrop(options)
+	wv.installDeepLinkDelivery()
+	wv.installDownloads()
+	wv.installPermissions()
+	wv.installRuntimeScript()
+	_, _ = wv.AddInitScript(fmt.Sprintf("window._wvappWindowId = %d;", id))
+	placed := false
+	if stateStore != nil {
+		if restoreState {
+			placed = wv.restoreWindowPlacement(savedState)
+		}
+		wv.trackWindowState(stateStore, options.StateKey, savedState)
+	}
+	if !placed {
+		if err := wv.applyPlacement(options); err != nil {
+			slog.Warn("Window placement ignored", "error", err)
+		}
+	}
+	return wv, nil
+}
+
+// newCWindowOptions 将 WindowOptions 转换为原生库的创建参数
+func newCWindowOptions(options *WindowOptions, titlePtr, iconPtr uintptr, iconLen uint64) *cWebviewWindowOptions {
+	position := options.Position
+	if position == WindowPositionCustom {
+		position = WindowPositionCenter // 原生库只认识预设位置，创建后再移动到目标坐标
+	}
+	return &cWebviewWindowOptions{
+		width:            int32(options.Width),
+		height:           int32(options.Height),
+		minWidth:         int32(options.MinWidth),
+		minHeight:        int32(options.MinHeight),
+		maxWidth:         int32(options.MaxWidth),
+		maxHeight:        int32(options.MaxHeight),
+		zoomLevel:        options.ZoomLevel,
+		position:         int32(position),
+		debug:            options.Debug,
+		title:            titlePtr,
+		icon:             iconPtr,
+		iconLen:          iconLen,
+		disableResize:    options.DisableResize,
+		opaque:           options.Opaque,
+		hasShadow:        options.HasShadow,
+		enableFileAccess: options.EnableFileAccess,
+		enableClipboard:  options.EnableClipboard,
+		enableWebGL:      options.EnableWebGL,
+	}
+}
+
+// loadWebviewLibrary 加载原生库并注册函数，可重复调用
+func loadWebviewLibrary() error {
 	loadOnce.Do(func() {
 		lib := libraryPath()
 		handle, err := loadLibrary(lib)
 		if err != nil {
-			initErr = fmt.Errorf("webview: failed to load library %s: %w", lib, err)
+			libraryInitErr = fmt.Errorf("webview: failed to load library %s: %w", lib, err)
 			return
 		}
 		purego.RegisterLibFunc(&webviewCreate, handle, "webview_create")
@@ -83,57 +297,65 @@ func NewWebview(options *WindowOptions) (*Webview, error) {
 		purego.RegisterLibFunc(&webviewMaximize, handle, "webview_maximize")
 		purego.RegisterLibFunc(&webviewMinimize, handle, "webview_minimize")
 		purego.RegisterLibFunc(&webviewRestore, handle, "webview_restore")
-	})
-	if initErr != nil {
-		return nil, initErr
-	}
-	atomic.AddInt32(&windowCount, 1)
 
-	var titlePtr, iconPtr uintptr
-	var iconLen uint64
-	var titleBytes, iconBytes []byte
-	if options.Title != "" {
-		titleBytes = append([]byte(options.Title), 0)
-		titlePtr = uintptr(unsafe.Pointer(&titleBytes[0]))
-	}
-	if len(options.Icon) > 0 {
-		iconBytes = options.Icon
-		iconPtr = uintptr(unsafe.Pointer(&iconBytes[0]))
-		iconLen = uint64(len(iconBytes))
-	}
-
-	cOptions := &cWebviewWindowOptions{
-		width:         int32(options.Width),
-		height:        int32(options.Height),
-		minWidth:      int32(options.MinWidth),
-		minHeight:     int32(options.MinHeight),
-		maxWidth:      int32(options.MaxWidth),
-		maxHeight:     int32(options.MaxHeight),
-		zoomLevel:     options.ZoomLevel,
-		position:      int32(options.Position),
-		debug:         options.Debug,
-		title:         titlePtr,
-		icon:          iconPtr,
-		iconLen:       iconLen,
-		disableResize: options.DisableResize,
-		opaque:        options.Opaque,
-		hasShadow:     options.HasShadow,
-	}
-
-	wv := mainScheduler.RunInMainThreadWithResult(func() any {
-		return webviewCreate(cOptions)
-	}).(*Webview)
-
-	runtime.KeepAlive(titleBytes)
-	runtime.KeepAlive(iconBytes)
+		// 以下为可选符号，旧版本原生库未导出时保持 nil，对应功能返回 ErrNotSupported
+		registerOptionalLibFunc(&webviewSetNavigationCallback, handle, "webview_set_navigation_callback")
+		registerOptionalLibFunc(&webviewSetContentSecurityPolicy, handle, "webview_set_content_security_policy")
+		registerOptionalLibFunc(&webviewSetEventDataCallback, handle, "webview_set_event_data_callback")
+		registerOptionalLibFunc(&webviewAddInitScript, handle, "webview_add_init_script")
+		registerOptionalLibFunc(&webviewClearInitScripts, handle, "webview_clear_init_scripts")
+		registerOptionalLibFunc(&webviewGetWindowState, handle, "webview_get_window_state")
+		registerOptionalLibFunc(&webviewFreeString, handle, "webview_free_string")
+		registerOptionalLibFunc(&webviewGetScreens, handle, "webview_get_screens")
+		registerOptionalLibFunc(&webviewSetPosition, handle, "webview_set_position")
+		registerOptionalLibFunc(&webviewFocus, handle, "webview_focus")
+		registerOptionalLibFunc(&webviewBringToFront, handle, "webview_bring_to_front")
+		registerOptionalLibFunc(&webviewSetParent, handle, "webview_set_parent")
+		registerOptionalLibFunc(&webviewSetAlwaysOnTop, handle, "webview_set_always_on_top")
+		registerOptionalLibFunc(&webviewSetSkipTaskbar, handle, "webview_set_skip_taskbar")
+		registerOptionalLibFunc(&webviewFileDialog, handle, "webview_file_dialog")
+		registerOptionalLibFunc(&webviewFileDialogAsync, handle, "webview_file_dialog_async")
+		registerOptionalLibFunc(&webviewMessageDialog, handle, "webview_message_dialog")
+		registerOptionalLibFunc(&webviewSetMenu, handle, "webview_set_menu")
+		registerOptionalLibFunc(&webviewSetMenuCallback, handle, "webview_set_menu_callback")
+		registerOptionalLibFunc(&webviewUpdateMenuItem, handle, "webview_update_menu_item")
+		registerOptionalLibFunc(&webviewShowContextMenu, handle, "webview_show_context_menu")
+		registerOptionalLibFunc(&webviewTrayCreate, handle, "webview_tray_create")
+		registerOptionalLibFunc(&webviewTraySetIcon, handle, "webview_tray_set_icon")
+		registerOptionalLibFunc(&webviewTraySetTooltip, handle, "webview_tray_set_tooltip")
+		registerOptionalLibFunc(&webviewTraySetMenu, handle, "webview_tray_set_menu")
+		registerOptionalLibFunc(&webviewTrayDestroy, handle, "webview_tray_destroy")
+		registerOptionalLibFunc(&webviewSetTrayCallback, handle, "webview_set_tray_callback")
+		registerOptionalLibFunc(&webviewClipboardRead, handle, "webview_clipboard_read")
+		registerOptionalLibFunc(&webviewClipboardWrite, handle, "webview_clipboard_write")
+		registerOptionalLibFunc(&webviewNotify, handle, "webview_notify")
+		registerOptionalLibFunc(&webviewSetFileDropOptions, handle, "webview_set_file_drop_options")
+		registerOptionalLibFunc(&webviewSetOpenURLCallback, handle, "webview_set_open_url_callback")
+		registerOptionalLibFunc(&webviewSetDataStore, handle, "webview_set_data_store")
+		registerOptionalLibFunc(&webviewDataRequest, handle, "webview_data_request")
+		registerOptionalLibFunc(&webviewSetDownloadCallback, handle, "webview_set_download_callback")
+		registerOptionalLibFunc(&webviewDownloadSetDestination, handle, "webview_download_set_destination")
+		registerOptionalLibFunc(&webviewDownloadCancel, handle, "webview_download_cancel")
+		registerOptionalLibFunc(&webviewSetPermissionCallback, handle, "webview_set_permission_callback")
+		registerOptionalLibFunc(&webviewPermissionRespond, handle, "webview_permission_respond")
+		registerOptionalLibFunc(&webviewSetHeadless, handle, "webview_set_headless")
+		registerOptionalLibFunc(&webviewPrint, handle, "webview_print")
+		registerOptionalLibFunc(&webviewPrintToPDF, handle, "webview_print_to_pdf")
+		registerOptionalLibFunc(&webviewCapture, handle, "webview_capture")
+		registerOptionalLibFunc(&webviewApplySettings, handle, "webview_apply_settings")
+		registerOptionalLibFunc(&webviewBridgeCaller, handle, "webview_bridge_caller")
+		registerOptionalLibFunc(&webviewSetHardwareAcceleration, handle, "webview_set_hardware_acceleration")
+	})
+	return libraryInitErr
+}
 
-	if wv == nil {
-		atomic.AddInt32(&windowCount, -1)
-		return nil, fmt.Errorf("webview: failed to create webview instance")
+// registerOptionalLibFunc 注册可选的原生函数，符号不存在时不做任何处理
+func registerOptionalLibFunc(fptr any, handle uintptr, name string) {
+	sym, err := lookupSymbol(handle, name)
+	if err != nil || sym == 0 {
+		return
 	}
-
-	wv.SetEventCallback(nil)
-	return wv, nil
+	purego.RegisterFunc(fptr, sym)
 }
 
 func (w *Webview) SetURL(url string) {
@@ -173,7 +395,12 @@ func (w *Webview) SetHtml(html string) {
 	})
 }
 
+// SetWindowPosition 将窗口移动到预设位置；WindowPositionCustom 没有坐标可用，会被忽略，请改用 SetPosition
 func (w *Webview) SetWindowPosition(position WindowPosition) {
+	if position < WindowPositionCenter || position >= WindowPositionCustom {
+		slog.Warn("Window position ignored, use SetPosition for custom coordinates", "position", position)
+		return
+	}
 	mainScheduler.RunInMainThread(func() { webviewSetWindowPosition(w, position) })
 }
 
@@ -201,27 +428,23 @@ func (w *Webview) EvalJS(js string) {
 	})
 }
 
+// SetEventCallback 设置单一的原始事件回调，需要多个订阅者或事件数据时请使用 On
 func (w *Webview) SetEventCallback(callback EventCallback) {
 	callbackWrapper := func(wv *Webview, eventType int32, userData unsafe.Pointer) uintptr {
 		if callback != nil {
 			callback(wv, EventType(eventType), userData)
 		}
-		switch EventType(eventType) {
-		case EventDomReady:
-			if len(runtimeJS) > 0 {
-				wv.EvalJS(unsafe.String(&runtimeJS[0], len(runtimeJS)))
-			}
-
-		case EventClose:
+		// 先补注入 runtime.js，DomReady 订阅者（例如补发深度链接）才能使用页面中的运行时
+		if EventType(eventType) == EventDomReady {
+			wv.onDomReadyFallback()
+		}
+		if ev, err := decodeEvent(EventType(eventType), nil); err == nil {
+			wv.dispatchEvent(ev)
+		}
+		if EventType(eventType) == EventClose {
 			// 窗口关闭时清理 Go 端资源
 			atomic.AddInt32(&windowCount, -1)
-			callbackMutex.Lock()
-			delete(callbackRegistry, wv)
-			callbackMutex.Unlock()
-
-			bindCallbackMutex.Lock()
-			delete(bindCallbackRegistry, wv)
-			bindCallbackMutex.Unlock()
+			releaseWindow(wv)
 		}
 		return 0 // 返回 uintptr 类型的值
 	}
@@ -235,6 +458,45 @@ func (w *Webview) SetEventCallback(callback EventCallback) {
 	})
 }
 
+// releaseWindow 清理窗口关闭后不再需要的 Go 端状态
+func releaseWindow(wv *Webview) {
+	// 父窗口关闭时一并关闭子窗口
+	for _, child := range unregisterWindow(wv) {
+		child.Terminate()
+	}
+
+	callbackMutex.Lock()
+	delete(callbackRegistry, wv)
+	callbackMutex.Unlock()
+
+	bindCallbackMutex.Lock()
+	delete(bindCallbackRegistry, wv)
+	bindCallbackMutex.Unlock()
+
+	bridgePolicyMutex.Lock()
+	delete(bridgePolicyRegistry, wv)
+	delete(bridgeMainURLs, wv)
+	bridgePolicyMutex.Unlock()
+
+	navigationMutex.Lock()
+	delete(navigationRegistry, wv)
+	navigationMutex.Unlock()
+
+	eventMutex.Lock()
+	delete(eventRegistry, wv)
+	eventMutex.Unlock()
+
+	initScriptMutex.Lock()
+	delete(initScriptRegistry, wv)
+	initScriptMutex.Unlock()
+
+	releaseMenus(wv)
+
+	releaseDownloads(wv)
+	releasePermissions(wv)
+	releaseDeepLinks(wv)
+}
+
 func (w *Webview) Bind(name string, fn BindCallback, userData unsafe.Pointer) {
 	if name == "" || fn == nil || w == nil {
 		return
@@ -254,9 +516,9 @@ func (w *Webview) Bind(name string, fn BindCallback, userData unsafe.Pointer) {
 
 	bindCallbackMutex.Lock()
 	if _, ok := bindCallbackRegistry[w]; !ok {
-		bindCallbackRegistry[w] = make(map[string]uintptr)
+		bindCallbackRegistry[w] = make(map[string]bindEntry)
 	}
-	bindCallbackRegistry[w][name] = cCallbackPtr
+	bindCallbackRegistry[w][name] = bindEntry{callback: cCallbackPtr, userData: userData}
 	bindCallbackMutex.Unlock()
 
 	mainScheduler.RunInMainThread(func() {
@@ -268,6 +530,25 @@ func (w *Webview) Bind(name string, fn BindCallback, userData unsafe.Pointer) {
 	runtime.KeepAlive(cstrName)
 }
 
+// rebindAll 重新注册窗口的全部绑定，使其在导航后继续可用。必须在主线程中调用。
+func (w *Webview) rebindAll() {
+	if webviewBind == nil {
+		return
+	}
+	bindCallbackMutex.Lock()
+	binds := make(map[string]bindEntry, len(bindCallbackRegistry[w]))
+	for name, entry := range bindCallbackRegistry[w] {
+		binds[name] = entry
+	}
+	bindCallbackMutex.Unlock()
+
+	for name, entry := range binds {
+		cstr, ptr := cString(name)
+		webviewBind(w, ptr, entry.callback, entry.userData)
+		runtime.KeepAlive(cstr)
+	}
+}
+
 func (w *Webview) Unbind(name string) {
 	cstrName, namePtr := cString(name)
 	mainScheduler.RunInMainThread(func() {
@@ -334,6 +615,15 @@ func (w *Webview) InitializeJavaScriptRuntime() {
 			return
 		}
 
+		if !w.allowBridgeCall(p) {
+			if p.PromiseID != 0 { // If JS expects a response
+				errorMsgJSON, _ := json.Marshal(fmt.Sprintf("Call to '%s' rejected: origin not allowed", p.Func))
+				rejectScript := fmt.Sprintf("window._rejectWebviewPromise(%d, %s);", p.PromiseID, string(errorMsgJSON))
+				w.EvalJS(rejectScript)
+			}
+			return
+		}
+
 		handler, ok := UserFunctionRegistry[p.Func]
 		if !ok {
 			fmt.Fprintf(os.Stderr, "Runtime Error: Function '%s' not found in UserFunctionRegistry.\n", p.Func)
@@ -0,0 +1,146 @@
+package wvapp
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// WindowID 窗口的稳定标识，进程内单调递增且不会复用
+type WindowID uint64
+
+type windowInfo struct {
+	id     WindowID
+	name   string
+	parent *Webview
+}
+
+var (
+	windowRegistry = make(map[*Webview]*windowInfo)
+	windowByID     = make(map[WindowID]*Webview)
+	windowMutex    sync.RWMutex
+	windowNextID   WindowID
+)
+
+type currentWindowKey struct{}
+
+// registerWindow 为新窗口分配 ID 并记录名称
+func registerWindow(w *Webview, name string) WindowID {
+	windowMutex.Lock()
+	defer windowMutex.Unlock()
+	windowNextID++
+	windowRegistry[w] = &windowInfo{id: windowNextID, name: name}
+	windowByID[windowNextID] = w
+	return windowNextID
+}
+
+// unregisterWindow 移除窗口记录，返回仍然打开的子窗口
+func unregisterWindow(w *Webview) []*Webview {
+	windowMutex.Lock()
+	defer windowMutex.Unlock()
+	if info, ok := windowRegistry[w]; ok {
+		delete(windowByID, info.id)
+		delete(windowRegistry, w)
+	}
+	var children []*Webview
+	for child, info := range windowRegistry {
+		if info.parent == w {
+			info.parent = nil
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
+// ID 返回窗口 ID，未注册（例如已关闭）的窗口返回 0
+func (w *Webview) ID() WindowID {
+	windowMutex.RLock()
+	defer windowMutex.RUnlock()
+	if info, ok := windowRegistry[w]; ok {
+		return info.id
+	}
+	return 0
+}
+
+// Name 返回窗口名称（WindowOptions.Name 或 SetName 设置）
+func (w *Webview) Name() string {
+	windowMutex.RLock()
+	defer windowMutex.RUnlock()
+	if info, ok := windowRegistry[w]; ok {
+		return info.name
+	}
+	return ""
+}
+
+// SetName 修改窗口名称
+func (w *Webview) SetName(name string) {
+	windowMutex.Lock()
+	defer windowMutex.Unlock()
+	if info, ok := windowRegistry[w]; ok {
+		info.name = name
+	}
+}
+
+// Windows 返回所有打开的窗口，按创建顺序排列
+func Windows() []*Webview {
+	windowMutex.RLock()
+	ids := make([]WindowID, 0, len(windowByID))
+	for id := range windowByID {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	windows := make([]*Webview, len(ids))
+	for i, id := range ids {
+		windows[i] = windowByID[id]
+	}
+	windowMutex.RUnlock()
+	return windows
+}
+
+// WindowByID 按 ID 查找窗口
+func WindowByID(id WindowID) *Webview {
+	windowMutex.RLock()
+	defer windowMutex.RUnlock()
+	return windowByID[id]
+}
+
+// WindowByName 按名称查找窗口，重名时返回最早创建的窗口
+func WindowByName(name string) *Webview {
+	for _, w := range Windows() {
+		if w.Name() == name {
+			return w
+		}
+	}
+	return nil
+}
+
+// Current 返回发起当前桥接调用的窗口，ctx 需来自 HandlerFunc 的参数
+func Current(ctx context.Context) *Webview {
+	if ctx == nil {
+		return nil
+	}
+	w, _ := ctx.Value(currentWindowKey{}).(*Webview)
+	return w
+}
+
+func withCurrentWindow(ctx context.Context, w *Webview) context.Context {
+	return context.WithValue(ctx, currentWindowKey{}, w)
+}
+
+// Focus 激活窗口并获取键盘焦点
+func (w *Webview) Focus() error {
+	if webviewFocus == nil {
+		return ErrNotSupported
+	}
+	mainScheduler.RunInMainThread(func() { webviewFocus(w) })
+	return nil
+}
+
+// BringToFront 将窗口置于其他窗口之上（不一定获取焦点）
+func (w *Webview) BringToFront() error {
+	if webviewBringToFront == nil {
+		return ErrNotSupported
+	}
+	mainScheduler.RunInMainThread(func() { webviewBringToFront(w) })
+	return nil
+}
@@ -0,0 +1,66 @@
+package wvapp
+
+import (
+	"context"
+	"testing"
+)
+
+func TestWindowRegistry(t *testing.T) {
+	main, settings, other := fakeWebview(), fakeWebview(), fakeWebview()
+	mainID := registerWindow(main, "main")
+	settingsID := registerWindow(settings, "settings")
+	otherID := registerWindow(other, "settings")
+	defer releaseWindow(main)
+	defer releaseWindow(settings)
+	defer releaseWindow(other)
+
+	if mainID == 0 || mainID >= settingsID || settingsID >= otherID {
+		t.Fatalf("IDs should be non-zero and increasing: %d %d %d", mainID, settingsID, otherID)
+	}
+	if main.ID() != mainID || main.Name() != "main" {
+		t.Fatalf("unexpected identity %d %q", main.ID(), main.Name())
+	}
+	if WindowByID(settingsID) != settings {
+		t.Fatal("WindowByID returned the wrong window")
+	}
+	if WindowByName("settings") != settings {
+		t.Fatal("WindowByName should return the earliest window with that name")
+	}
+	if WindowByName("missing") != nil {
+		t.Fatal("unknown names should not match")
+	}
+
+	windows := Windows()
+	var seen []*Webview
+	for _, w := range windows {
+		if w == main || w == settings || w == other {
+			seen = append(seen, w)
+		}
+	}
+	if len(seen) != 3 || seen[0] != main || seen[1] != settings || seen[2] != other {
+		t.Fatalf("Windows() should list windows in creation order, got %v", seen)
+	}
+
+	other.SetName("palette")
+	if WindowByName("palette") != other {
+		t.Fatal("SetName should rename the window")
+	}
+
+	releaseWindow(settings)
+	if settings.ID() != 0 || WindowByID(settingsID) != nil {
+		t.Fatal("closed windows must be removed from the registry")
+	}
+	if WindowByName("settings") != nil {
+		t.Fatal("closed windows must not be found by name")
+	}
+}
+
+func TestCurrentWindow(t *testing.T) {
+	wv := fakeWebview()
+	if Current(context.Background()) != nil {
+		t.Fatal("context without window should return nil")
+	}
+	if Current(withCurrentWindow(context.Background(), wv)) != wv {
+		t.Fatal("Current should return the window stored in the context")
+	}
+}
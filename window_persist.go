@@ -0,0 +1,278 @@
+package wvapp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// minVisibleWindowPart 恢复位置时窗口至少需要有这么多像素（宽、高）落在某个显示器的可用区域内
+const minVisibleWindowPart = 64
+
+// SavedWindowState 持久化的窗口几何信息
+type SavedWindowState struct {
+	X         int     `json:"x"`
+	Y         int     `json:"y"`
+	Width     int     `json:"width"`
+	Height    int     `json:"height"`
+	Maximized bool    `json:"maximized"`
+	Zoom      float32 `json:"zoom,omitempty"`
+}
+
+// WindowStateStore 以 JSON 文件保存多个窗口的状态，键为 WindowOptions.StateKey
+type WindowStateStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewWindowStateStore 创建使用指定文件的状态存储
+func NewWindowStateStore(path string) *WindowStateStore {
+	return &WindowStateStore{path: path}
+}
+
+// DefaultWindowStatePath 默认状态文件：<用户配置目录>/<程序名>/window-state.json
+func DefaultWindowStatePath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, applicationName(), "window-state.json"), nil
+}
+
+// applicationName 可执行文件名（不含扩展名），用作配置目录名与通知来源
+func applicationName() string {
+	exe, err := os.Executable()
+	if err != nil {
+		return filepath.Base(os.Args[0])
+	}
+	return strings.TrimSuffix(filepath.Base(exe), filepath.Ext(exe))
+}
+
+var (
+	windowStateStore     *WindowStateStore
+	windowStateStoreOnce sync.Once
+	windowStateStoreMu   sync.Mutex
+)
+
+// SetWindowStateStore 替换全局状态存储（例如改为应用自己的配置目录）
+func SetWindowStateStore(store *WindowStateStore) {
+	windowStateStoreOnce.Do(func() {})
+	windowStateStoreMu.Lock()
+	windowStateStore = store
+	windowStateStoreMu.Unlock()
+}
+
+func defaultWindowStateStore() *WindowStateStore {
+	windowStateStoreOnce.Do(func() {
+		path, err := DefaultWindowStatePath()
+		if err != nil {
+			slog.Warn("Window state persistence disabled", "error", err)
+			return
+		}
+		windowStateStore = NewWindowStateStore(path)
+	})
+	windowStateStoreMu.Lock()
+	defer windowStateStoreMu.Unlock()
+	return windowStateStore
+}
+
+func (s *WindowStateStore) readAll() (map[string]SavedWindowState, error) {
+	states := make(map[string]SavedWindowState)
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return states, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &states); err != nil {
+		return nil, fmt.Errorf("window state file %s is corrupt: %w", s.path, err)
+	}
+	return states, nil
+}
+
+// Load 读取 key 对应的状态
+func (s *WindowStateStore) Load(key string) (SavedWindowState, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	states, err := s.readAll()
+	if err != nil {
+		return SavedWindowState{}, false, err
+	}
+	state, ok := states[key]
+	return state, ok, nil
+}
+
+// Save 保存 key 对应的状态，写入临时文件后重命名，避免写到一半时损坏已有数据
+func (s *WindowStateStore) Save(key string, state SavedWindowState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	states, err := s.readAll()
+	if err != nil {
+		// 损坏的文件直接覆盖
+		states = make(map[string]SavedWindowState)
+	}
+	states[key] = state
+	data, err := json.MarshalIndent(states, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".window-state-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// placeOnScreens 校验保存的位置是否仍在某个已连接显示器上，并把尺寸限制在该显示器可用区域内
+func placeOnScreens(saved SavedWindowState, screens []Screen) (Rect, bool) {
+	win := Rect{X: saved.X, Y: saved.Y, Width: saved.Width, Height: saved.Height}
+	if win.Empty() {
+		return Rect{}, false
+	}
+	// 标题栏所在的顶部区域必须可见，否则用户无法拖动窗口
+	titleBar := Rect{X: win.X, Y: win.Y, Width: win.Width, Height: min(win.Height, minVisibleWindowPart)}
+	for _, screen := range screens {
+		area := screen.usableArea()
+		visible := titleBar.Intersect(area)
+		if visible.Width < min(win.Width, minVisibleWindowPart) || visible.Height < titleBar.Height/2 {
+			continue
+		}
+		win.Width = min(win.Width, area.Width)
+		win.Height = min(win.Height, area.Height)
+		return win, true
+	}
+	return Rect{}, false
+}
+
+// restoreWindowOptions 用保存的状态覆盖窗口选项中的尺寸与缩放，返回应用后的副本
+func restoreWindowOptions(options *WindowOptions, saved SavedWindowState) *WindowOptions {
+	restored := *options
+	if saved.Width > 0 && saved.Height > 0 {
+		restored.Width = saved.Width
+		restored.Height = saved.Height
+	}
+	if saved.Zoom > 0 {
+		restored.ZoomLevel = saved.Zoom
+	}
+	return &restored
+}
+
+// restoreWindowPlacement 窗口创建后恢复位置与最大化状态，返回位置是否已恢复
+func (w *Webview) restoreWindowPlacement(saved SavedWindowState) bool {
+	defer func() {
+		if saved.Maximized {
+			w.Maximize()
+		}
+	}()
+	screens, err := Screens()
+	if err != nil {
+		slog.Debug("Skipping window position restore", "error", err)
+		return false
+	}
+	rect, ok := placeOnScreens(saved, screens)
+	if !ok {
+		return false
+	}
+	if err := w.SetPosition(rect.X, rect.Y); err != nil {
+		slog.Debug("Skipping window position restore", "error", err)
+		return false
+	}
+	if rect.Width != saved.Width || rect.Height != saved.Height {
+		w.SetSize(rect.Width, rect.Height)
+	}
+	return true
+}
+
+// normalBounds 记录窗口最近一次处于常规状态（非最大化/全屏/最小化）时的尺寸与位置，
+// 以及最近一次读取到的完整状态，关闭时原生窗口可能已无法查询
+type normalBounds struct {
+	mu   sync.Mutex
+	rect Rect
+	last WindowState
+}
+
+// update 总是记录最新状态，仅在窗口处于常规状态时更新尺寸与位置
+func (b *normalBounds) update(state WindowState) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.last = state
+	if state.Maximized || state.Fullscreen || state.Minimized || state.Width <= 0 || state.Height <= 0 {
+		return
+	}
+	b.rect = Rect{X: state.X, Y: state.Y, Width: state.Width, Height: state.Height}
+}
+
+func (b *normalBounds) get() Rect {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.rect
+}
+
+func (b *normalBounds) lastState() WindowState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.last
+}
+
+// trackWindowState 跟踪常规状态下的尺寸与位置，并在窗口关闭时保存状态
+func (w *Webview) trackWindowState(store *WindowStateStore, key string, previous SavedWindowState) {
+	bounds := &normalBounds{
+		rect: Rect{X: previous.X, Y: previous.Y, Width: previous.Width, Height: previous.Height},
+		last: WindowState{Maximized: previous.Maximized, Zoom: previous.Zoom},
+	}
+	record := func(wv *Webview, _ Event) {
+		if state, err := wv.State(); err == nil {
+			bounds.update(state)
+		}
+	}
+	record(w, nil)
+	w.On(EventMove, record)
+	w.On(EventResize, record)
+	w.On(EventRestore, record)
+	w.On(EventMaximize, record)
+	w.On(EventClose, func(wv *Webview, _ Event) {
+		if err := store.Save(key, bounds.closingState(wv)); err != nil {
+			slog.Warn("Failed to save window state", "key", key, "error", err)
+		}
+	})
+}
+
+// closingState 关闭时读取窗口状态；原生窗口已在销毁而无法查询时使用最近一次记录的状态
+func (b *normalBounds) closingState(wv *Webview) SavedWindowState {
+	if state, err := wv.State(); err == nil {
+		b.update(state)
+	} else {
+		slog.Debug("Using last known window state on close", "error", err)
+	}
+	return savedStateFrom(b.lastState(), b.get())
+}
+
+// savedStateFrom 保存最近一次常规状态下的尺寸与位置；最大化/全屏/最小化只记录最大化标志
+func savedStateFrom(state WindowState, normal Rect) SavedWindowState {
+	return SavedWindowState{
+		X:         normal.X,
+		Y:         normal.Y,
+		Width:     normal.Width,
+		Height:    normal.Height,
+		Maximized: state.Maximized,
+		Zoom:      state.Zoom,
+	}
+}
@@ -0,0 +1,136 @@
+package wvapp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWindowStateStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app", "window-state.json")
+	store := NewWindowStateStore(path)
+
+	if _, ok, err := store.Load("main"); err != nil || ok {
+		t.Fatalf("empty store: ok=%v err=%v", ok, err)
+	}
+
+	main := SavedWindowState{X: 10, Y: 20, Width: 800, Height: 600, Zoom: 1.25}
+	settings := SavedWindowState{X: 100, Y: 120, Width: 400, Height: 300, Maximized: true}
+	if err := store.Save("main", main); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Save("settings", settings); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened := NewWindowStateStore(path)
+	for key, want := range map[string]SavedWindowState{"main": main, "settings": settings} {
+		got, ok, err := reopened.Load(key)
+		if err != nil || !ok || got != want {
+			t.Fatalf("Load(%q) = %+v, %v, %v; want %+v", key, got, ok, err, want)
+		}
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Fatalf("temporary files left behind: %v", entries)
+	}
+}
+
+func TestWindowStateStoreCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "window-state.json")
+	if err := os.WriteFile(path, []byte("{not json"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	store := NewWindowStateStore(path)
+	if _, _, err := store.Load("main"); err == nil {
+		t.Fatal("expected error for corrupt state file")
+	}
+	state := SavedWindowState{Width: 640, Height: 480}
+	if err := store.Save("main", state); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok, err := store.Load("main"); err != nil || !ok || got != state {
+		t.Fatalf("store not recovered: %+v %v %v", got, ok, err)
+	}
+}
+
+func TestPlaceOnScreens(t *testing.T) {
+	screens := []Screen{
+		{Bounds: Rect{0, 0, 1920, 1080}, WorkArea: Rect{0, 0, 1920, 1040}, Primary: true},
+		{Bounds: Rect{1920, 0, 1280, 1024}},
+	}
+	cases := []struct {
+		name  string
+		saved SavedWindowState
+		want  Rect
+		ok    bool
+	}{
+		{"primary", SavedWindowState{X: 100, Y: 100, Width: 800, Height: 600}, Rect{100, 100, 800, 600}, true},
+		{"secondary", SavedWindowState{X: 2000, Y: 50, Width: 800, Height: 600}, Rect{2000, 50, 800, 600}, true},
+		{"partly off screen", SavedWindowState{X: 1800, Y: 10, Width: 400, Height: 300}, Rect{1800, 10, 400, 300}, true},
+		{"clamped to work area", SavedWindowState{X: 0, Y: 0, Width: 2500, Height: 1400}, Rect{0, 0, 1920, 1040}, true},
+		{"disconnected monitor", SavedWindowState{X: -1600, Y: 0, Width: 800, Height: 600}, Rect{}, false},
+		{"title bar below work area", SavedWindowState{X: 100, Y: 1060, Width: 800, Height: 600}, Rect{}, false},
+		{"empty", SavedWindowState{}, Rect{}, false},
+	}
+	for _, c := range cases {
+		got, ok := placeOnScreens(c.saved, screens)
+		if ok != c.ok || got != c.want {
+			t.Errorf("%s: got %+v, %v; want %+v, %v", c.name, got, ok, c.want, c.ok)
+		}
+	}
+	if _, ok := placeOnScreens(SavedWindowState{X: 0, Y: 0, Width: 100, Height: 100}, nil); ok {
+		t.Error("no screens should never validate a position")
+	}
+}
+
+func TestSavedStateFrom(t *testing.T) {
+	bounds := &normalBounds{rect: Rect{X: 10, Y: 20, Width: 800, Height: 600}}
+
+	// 常规状态下的移动与缩放会更新记录
+	bounds.update(WindowState{X: 30, Y: 40, Width: 1024, Height: 768, Zoom: 1.5})
+	normal := savedStateFrom(WindowState{X: 30, Y: 40, Width: 1024, Height: 768, Zoom: 1.5}, bounds.get())
+	if normal != (SavedWindowState{X: 30, Y: 40, Width: 1024, Height: 768, Zoom: 1.5}) {
+		t.Fatalf("unexpected state %+v", normal)
+	}
+
+	// 最大化、全屏与最小化不覆盖常规尺寸
+	for _, state := range []WindowState{
+		{X: 0, Y: 0, Width: 1920, Height: 1040, Maximized: true},
+		{X: 0, Y: 0, Width: 1920, Height: 1080, Fullscreen: true},
+		{X: -32000, Y: -32000, Width: 160, Height: 28, Minimized: true},
+	} {
+		bounds.update(state)
+	}
+	maximized := savedStateFrom(WindowState{X: 0, Y: 0, Width: 1920, Height: 1040, Maximized: true}, bounds.get())
+	if maximized != (SavedWindowState{X: 30, Y: 40, Width: 1024, Height: 768, Maximized: true}) {
+		t.Fatalf("maximized window should keep its latest normal bounds, got %+v", maximized)
+	}
+}
+
+func TestClosingStateWithoutNativeState(t *testing.T) {
+	if webviewGetWindowState != nil {
+		t.Skip("native library provides window state")
+	}
+	bounds := &normalBounds{}
+	bounds.update(WindowState{X: 30, Y: 40, Width: 1024, Height: 768, Zoom: 1.25})
+	bounds.update(WindowState{X: 0, Y: 0, Width: 1920, Height: 1040, Maximized: true, Zoom: 1.25})
+
+	// 关闭时无法查询状态，仍保存最近一次记录的状态
+	saved := bounds.closingState(fakeWebview())
+	if saved != (SavedWindowState{X: 30, Y: 40, Width: 1024, Height: 768, Maximized: true, Zoom: 1.25}) {
+		t.Fatalf("unexpected state on close %+v", saved)
+	}
+}
+
+func TestRestoreWindowOptions(t *testing.T) {
+	options := &WindowOptions{Width: 800, Height: 600, ZoomLevel: 1, Title: "App"}
+	restored := restoreWindowOptions(options, SavedWindowState{Width: 1200, Height: 900, Zoom: 1.5})
+	if restored.Width != 1200 || restored.Height != 900 || restored.ZoomLevel != 1.5 || restored.Title != "App" {
+		t.Fatalf("unexpected restored options %+v", restored)
+	}
+	if options.Width != 800 || options.ZoomLevel != 1 {
+		t.Fatal("caller's options must not be modified")
+	}
+}
@@ -0,0 +1,97 @@
+package wvapp
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+)
+
+// Parent 返回父窗口，独立窗口返回 nil
+func (w *Webview) Parent() *Webview {
+	windowMutex.RLock()
+	defer windowMutex.RUnlock()
+	if info, ok := windowRegistry[w]; ok {
+		return info.parent
+	}
+	return nil
+}
+
+// Children 返回仍然打开的子窗口，按创建顺序排列
+func (w *Webview) Children() []*Webview {
+	windowMutex.RLock()
+	var children []*Webview
+	for child, info := range windowRegistry {
+		if info.parent == w {
+			children = append(children, child)
+		}
+	}
+	windowMutex.RUnlock()
+	sort.Slice(children, func(i, j int) bool { return children[i].ID() < children[j].ID() })
+	return children
+}
+
+// SetParent 设置父窗口：子窗口始终显示在父窗口之上，父窗口关闭时子窗口随之关闭。
+// parent 为 nil 时解除关系；modal 为 true 时子窗口打开期间父窗口不响应输入。
+func (w *Webview) SetParent(parent *Webview, modal bool) error {
+	if parent == w && w != nil {
+		return fmt.Errorf("webview: a window cannot be its own parent")
+	}
+	if modal && parent == nil {
+		return fmt.Errorf("webview: modal windows require a parent")
+	}
+	for p := parent; p != nil; p = p.Parent() {
+		if p == w {
+			return fmt.Errorf("webview: parent relationship would create a cycle")
+		}
+	}
+	if webviewSetParent == nil {
+		return ErrNotSupported
+	}
+
+	windowMutex.Lock()
+	if info, ok := windowRegistry[w]; ok {
+		info.parent = parent
+	}
+	windowMutex.Unlock()
+
+	mainScheduler.RunInMainThread(func() { webviewSetParent(w, parent, modal) })
+	return nil
+}
+
+// SetAlwaysOnTop 设置窗口是否总在最前
+func (w *Webview) SetAlwaysOnTop(onTop bool) error {
+	if webviewSetAlwaysOnTop == nil {
+		return ErrNotSupported
+	}
+	mainScheduler.RunInMainThread(func() { webviewSetAlwaysOnTop(w, onTop) })
+	return nil
+}
+
+// SetSkipTaskbar 设置窗口是否在任务栏/Dock 中隐藏
+func (w *Webview) SetSkipTaskbar(skip bool) error {
+	if webviewSetSkipTaskbar == nil {
+		return ErrNotSupported
+	}
+	mainScheduler.RunInMainThread(func() { webviewSetSkipTaskbar(w, skip) })
+	return nil
+}
+
+// applyRelationOptions 应用 Parent/Modal/AlwaysOnTop/SkipTaskbar 选项。
+// Parent/Modal 无法应用时返回错误，模态窗口不能退化为不阻塞父窗口的普通窗口；
+// AlwaysOnTop/SkipTaskbar 只影响外观，不支持时仅记录警告
+func (w *Webview) applyRelationOptions(options *WindowOptions) error {
+	if options.AlwaysOnTop {
+		if err := w.SetAlwaysOnTop(true); err != nil {
+			slog.Warn("AlwaysOnTop ignored", "error", err)
+		}
+	}
+	if options.SkipTaskbar {
+		if err := w.SetSkipTaskbar(true); err != nil {
+			slog.Warn("SkipTaskbar ignored", "error", err)
+		}
+	}
+	if options.Parent != nil || options.Modal {
+		return w.SetParent(options.Parent, options.Modal)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package wvapp
+
+import (
+	"errors"
+	"testing"
+)
+
+func setTestParent(child, parent *Webview) {
+	windowMutex.Lock()
+	windowRegistry[child].parent = parent
+	windowMutex.Unlock()
+}
+
+func TestParentChildRegistry(t *testing.T) {
+	main, dialog, palette := fakeWebview(), fakeWebview(), fakeWebview()
+	registerWindow(main, "main")
+	registerWindow(dialog, "dialog")
+	registerWindow(palette, "palette")
+	defer unregisterWindow(dialog)
+	defer unregisterWindow(palette)
+
+	setTestParent(dialog, main)
+	setTestParent(palette, main)
+
+	if dialog.Parent() != main || main.Parent() != nil {
+		t.Fatal("unexpected parent relationship")
+	}
+	children := main.Children()
+	if len(children) != 2 || children[0] != dialog || children[1] != palette {
+		t.Fatalf("unexpected children %v", children)
+	}
+
+	orphans := unregisterWindow(main)
+	if len(orphans) != 2 {
+		t.Fatalf("closing the parent should report its children, got %v", orphans)
+	}
+	if dialog.Parent() != nil {
+		t.Fatal("children should be detached from a closed parent")
+	}
+}
+
+func TestSetParentValidation(t *testing.T) {
+	main, child := fakeWebview(), fakeWebview()
+	registerWindow(main, "")
+	registerWindow(child, "")
+	defer unregisterWindow(main)
+	defer unregisterWindow(child)
+
+	if err := main.SetParent(main, false); err == nil {
+		t.Error("a window must not be its own parent")
+	}
+	if err := child.SetParent(nil, true); err == nil {
+		t.Error("modal windows require a parent")
+	}
+
+	setTestParent(child, main)
+	if err := main.SetParent(child, false); err == nil || errors.Is(err, ErrNotSupported) {
+		t.Errorf("expected cycle error, got %v", err)
+	}
+}
+
+func TestApplyRelationOptionsRequiresParentSupport(t *testing.T) {
+	if webviewSetParent != nil || webviewSetAlwaysOnTop != nil {
+		t.Skip("native library provides window relations")
+	}
+	main, dialog := fakeWebview(), fakeWebview()
+	registerWindow(main, "main")
+	registerWindow(dialog, "dialog")
+	defer unregisterWindow(main)
+	defer unregisterWindow(dialog)
+
+	// 外观选项不支持时忽略
+	if err := dialog.applyRelationOptions(&WindowOptions{AlwaysOnTop: true, SkipTaskbar: true}); err != nil {
+		t.Fatalf("cosmetic options should not fail, got %v", err)
+	}
+	// 模态窗口不能静默退化为普通窗口
+	err := dialog.applyRelationOptions(&WindowOptions{Parent: main, Modal: true})
+	if !errors.Is(err, ErrNotSupported) {
+		t.Fatalf("unsupported modal parent should fail, got %v", err)
+	}
+	if dialog.Parent() != nil {
+		t.Fatal("parent must not be recorded when it cannot be applied")
+	}
+}
@@ -0,0 +1,96 @@
+package wvapp
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// WindowState 窗口当前状态，坐标与尺寸均为逻辑像素
+type WindowState struct {
+	X          int     `json:"x"`
+	Y          int     `json:"y"`
+	Width      int     `json:"width"`
+	Height     int     `json:"height"`
+	Maximized  bool    `json:"maximized"`
+	Minimized  bool    `json:"minimized"`
+	Fullscreen bool    `json:"fullscreen"`
+	Visible    bool    `json:"visible"`
+	Focused    bool    `json:"focused"`
+	Title      string  `json:"title"`
+	URL        string  `json:"url"`
+	Monitor    int     `json:"monitor"` // 窗口所在显示器的索引
+	Zoom       float32 `json:"zoom"`    // 页面缩放级别（1表示100%）
+}
+
+type windowStateResult struct {
+	state WindowState
+	err   error
+}
+
+// State 同步读取窗口状态（通过主线程调度器执行）
+func (w *Webview) State() (WindowState, error) {
+	if w == nil {
+		return WindowState{}, fmt.Errorf("webview: nil webview")
+	}
+	if webviewGetWindowState == nil || webviewFreeString == nil {
+		return WindowState{}, ErrNotSupported
+	}
+	res := mainScheduler.RunInMainThreadWithResult(func() any {
+		ptr := webviewGetWindowState(w)
+		if ptr == 0 {
+			return windowStateResult{err: fmt.Errorf("webview: failed to query window state")}
+		}
+		data := goString(ptr)
+		webviewFreeString(ptr)
+		var state WindowState
+		if err := json.Unmarshal([]byte(data), &state); err != nil {
+			return windowStateResult{err: fmt.Errorf("webview: invalid window state: %w", err)}
+		}
+		return windowStateResult{state: state}
+	}).(windowStateResult)
+	return res.state, res.err
+}
+
+// Size 返回窗口当前宽高
+func (w *Webview) Size() (width, height int, err error) {
+	s, err := w.State()
+	return s.Width, s.Height, err
+}
+
+// Position 返回窗口左上角的屏幕坐标
+func (w *Webview) Position() (x, y int, err error) {
+	s, err := w.State()
+	return s.X, s.Y, err
+}
+
+// Title 返回窗口当前标题
+func (w *Webview) Title() (string, error) {
+	s, err := w.State()
+	return s.Title, err
+}
+
+// URL 返回主 frame 当前的 URL
+func (w *Webview) URL() (string, error) {
+	s, err := w.State()
+	return s.URL, err
+}
+
+func (w *Webview) IsMaximized() (bool, error) {
+	s, err := w.State()
+	return s.Maximized, err
+}
+
+func (w *Webview) IsMinimized() (bool, error) {
+	s, err := w.State()
+	return s.Minimized, err
+}
+
+func (w *Webview) IsFullscreen() (bool, error) {
+	s, err := w.State()
+	return s.Fullscreen, err
+}
+
+func (w *Webview) IsVisible() (bool, error) {
+	s, err := w.State()
+	return s.Visible, err
+}
@@ -16,6 +16,9 @@ type CallPayload struct {
 	Func      string `json:"func"`
 	Args      []any  `json:"args"`
 	PromiseID int    `json:"promiseId,omitempty"` // omitempty if JS doesn't always send it
+	Origin    string `json:"origin,omitempty"`    // Origin of the calling page (window.location.origin)
+	Frame     string `json:"frame,omitempty"`     // FrameMain or FrameSubframe
+	URL       string `json:"url,omitempty"`       // URL of the calling frame, used for audit logs
 }
 
 type HandlerFunc func(ctx context.Context, wv *Webview, args []any) (result any, err error)
@@ -94,11 +97,11 @@ func (wp *WorkerPool) processJob(job Job) {
 			}
 		}
 	}()
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second) // Set a timeout for job execution
-	defer cancel()                                                           // Ensure the context is cancelled after job execution
+	ctx, cancel := context.WithTimeout(withCurrentWindow(context.Background(), job.Webview), 30*time.Second) // Set a timeout for job execution
+	defer cancel()                                                                                           // Ensure the context is cancelled after job execution
 	result, err := job.Handler(ctx, job.Webview, job.Payload.Args)
 
-	slog.Debug("Processing job", "function", job.Payload.Func, "args", job.Payload.Args, "result", result, "error", err)
+	slog.Debug("Processing job", "window", job.Webview.ID(), "function", job.Payload.Func, "args", job.Payload.Args, "result", result, "error", err)
 	// If PromiseID is 0 or not set, JS might not be expecting a specific promise resolution.
 	// Adjust this condition based on how your JS `goCall` sends PromiseID.
 	// If goCall *always* sends a promiseId when expectResponse=true, then this check is fine.
@@ -21,6 +21,7 @@ const (
 	WindowPositionRightTop
 	WindowPositionLeftBottom
 	WindowPositionRightBottom
+	WindowPositionCustom // 使用 WindowOptions.X/Y 指定的坐标
 )
 
 // 窗口选项结构体（仅用于初始化或批量设置）
@@ -42,6 +43,36 @@ type WindowOptions struct {
 	EnableFileAccess bool           // 是否启用本地文件访问
 	EnableClipboard  bool           // 是否启用剪贴板访问
 	EnableWebGL      bool           // 是否启用WebGL
+	BridgePolicy     *OriginPolicy  // 允许调用 Go 桥接函数的来源（nil表示默认规则，见 SetBridgeOriginPolicy）
+
+	NavigationPolicy      *NavigationPolicy // 导航白名单（nil表示不限制）
+	OnNavigate            NavigateHandler   // 导航回调，返回值优先于 NavigationPolicy
+	ContentSecurityPolicy string            // 注入到 URI scheme 页面的 CSP（空表示不设置）
+
+	StateKey string // 非空时在关闭时保存窗口尺寸、位置、最大化状态与缩放，并在下次创建时恢复
+
+	X          int      // Position 为 WindowPositionCustom 时的横坐标（相对 Screen/RelativeTo 的左上角，否则为屏幕绝对坐标）
+	Y          int      // Position 为 WindowPositionCustom 时的纵坐标
+	Screen     *Screen  // 在指定显示器的工作区内按 Position 放置窗口（来自 Screens()）
+	RelativeTo *Webview // 相对该窗口按 Position 放置（例如居中于父窗口），优先于 Screen
+
+	Name string // 窗口名称，可用 WindowByName 查找
+
+	Parent      *Webview // 父窗口：子窗口显示在父窗口之上并随父窗口关闭，默认相对父窗口放置
+	Modal       bool     // 模态窗口，打开期间阻止父窗口输入（需要 Parent）
+	AlwaysOnTop bool     // 总在最前
+	SkipTaskbar bool     // 不在任务栏/Dock 中显示
+
+	Menu *Menu // 窗口菜单栏（nil 表示使用 SetApplicationMenu 设置的应用菜单）
+
+	FileDrop FileDropOptions // 文件拖放行为
+
+	DataDir   string // Cookie、缓存与网页存储的目录，窗口间使用相同目录时共享数据（空表示默认配置）
+	Ephemeral bool   // 数据只保存在内存中，窗口关闭后丢弃（不能与 DataDir 同时使用）
+
+	Headless bool // 离屏窗口：正常加载与渲染页面但从不显示，用于 PrintToPDF 等无界面任务
+
+	WebSettings WebSettings // User-Agent、JavaScript、自动播放、字号等网页设置，运行时用 SetWebSettings 修改
 }
 
 type cWebviewWindowOptions struct {
@@ -72,22 +103,69 @@ type EventType int
 const (
 	EventClose EventType = iota
 	EventDomReady
+	EventNavigationStarted  // 开始导航，携带 URL
+	EventNavigationFinished // 导航完成，携带 URL
+	EventLoadFailed         // 加载失败，携带 URL 与错误码
+	EventTitleChanged       // 页面标题变化
+	EventFocus              // 窗口获得焦点
+	EventBlur               // 窗口失去焦点
+	EventResize             // 窗口大小变化，携带新尺寸
+	EventMove               // 窗口位置变化，携带新坐标
+	EventMinimize           // 窗口最小化
+	EventMaximize           // 窗口最大化
+	EventRestore            // 窗口从最小化/最大化恢复
+	EventFullscreenChanged  // 全屏状态变化
+	EventFileDrop           // 文件拖放到窗口，携带文件路径与坐标
+	EventDownloadProgress   // 下载进度，携带已接收与总字节数
+	EventDownloadFinished   // 下载完成、取消或失败
 )
 
 type EventCallback func(wv *Webview, eventType EventType, userData unsafe.Pointer)
 
 type BindCallback func(req string, userData unsafe.Pointer)
 
+// bindEntry 记录绑定信息，用于导航后重新绑定
+type bindEntry struct {
+	callback uintptr
+	userData unsafe.Pointer
+}
+
 var (
 	mainScheduler        = NewScheduler()
 	windowCount          int32
 	callbackRegistry     = make(map[*Webview]uintptr)
 	callbackMutex        sync.Mutex
-	bindCallbackRegistry = make(map[*Webview]map[string]uintptr)
+	bindCallbackRegistry = make(map[*Webview]map[string]bindEntry)
 	bindCallbackMutex    sync.Mutex
 	runnerOnce           sync.Once
+
+	keepAlive     atomic.Bool // 最后一个窗口关闭后是否继续运行事件循环
+	quitRequested atomic.Bool
 )
 
+// SetKeepAlive 设置最后一个窗口关闭后 Run() 是否继续运行（例如只保留托盘图标的后台程序），
+// 此时需要调用 Quit() 退出
+func SetKeepAlive(enabled bool) {
+	keepAlive.Store(enabled)
+}
+
+// Quit 关闭所有窗口并让 Run() 返回
+func Quit() {
+	for _, w := range Windows() {
+		w.Terminate()
+	}
+	// 排在关闭窗口的任务之后，保证窗口先被关闭
+	mainScheduler.RunInMainThread(func() { quitRequested.Store(true) })
+}
+
+// shouldExitLoop 事件循环是否应该退出，done 为 webviewProcessEvents 的返回值
+func shouldExitLoop(done bool) bool {
+	if quitRequested.Load() {
+		return true
+	}
+	return done && atomic.LoadInt32(&windowCount) <= 0 && !keepAlive.Load()
+}
+
 func Run() {
 	runnerOnce.Do(func() {
 		runtime.LockOSThread()
@@ -97,10 +175,8 @@ func Run() {
 
 		for {
 			mainScheduler.PollTasks()
-			if done := webviewProcessEvents(); done {
-				if atomic.LoadInt32(&windowCount) <= 0 {
-					break
-				}
+			if shouldExitLoop(webviewProcessEvents()) {
+				break
 			}
 			time.Sleep(time.Millisecond * 5)
 		}